@@ -3,14 +3,20 @@ package main
 import (
 	"chat-go-api/internal/handlers"
 	"chat-go-api/internal/middleware"
+	"chat-go-api/internal/models"
 	"chat-go-api/internal/repository"
+	"chat-go-api/internal/search"
 	"chat-go-api/internal/services"
+	"chat-go-api/internal/storage"
 	"chat-go-api/internal/websocket"
 	"chat-go-api/pkg/utils"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/joho/godotenv"
@@ -43,44 +49,158 @@ func main() {
 	go wsManager.Run() // WebSocket 매니저 실행
 
 	// EmailService 초기화
+	if config.Server.FrontendURL == "" {
+		log.Fatalf("Invalid server config: server.frontend_url is not set")
+	}
 	emailService := services.NewEmailService(
 		os.Getenv("SMTP_HOST"),
 		os.Getenv("SMTP_PORT"),
 		os.Getenv("SMTP_USERNAME"),
 		os.Getenv("SMTP_PASSWORD"),
+		config.Server.FrontendURL,
 		100,
 		1,
 	)
 
 	// AuthService 초기화
 	userRepo := repository.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo, emailService, "access-secret-key", "refresh-secret-key")
+	if err := userRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("Failed to create user indexes: %v", err)
+	}
+	authService := services.NewAuthService(userRepo, emailService, wsManager, "access-secret-key", "refresh-secret-key")
 
 	// 핸들러 초기화
-	authHandler := handlers.NewAuthHandler(authService)
+	trustedProxies, err := config.Server.TrustedProxyPrefixes()
+	if err != nil {
+		log.Fatalf("Invalid server config: %v", err)
+	}
+	authHandler := handlers.NewAuthHandler(authService, trustedProxies)
 
 	// ChatService 및 ChatHandler 초기화
 	chatRepo := repository.NewChatRepository(db)
+	if err := chatRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("Failed to create chat room indexes: %v", err)
+	}
 	messageRepo := repository.NewMessageRepository(db)
-	chatService := services.NewChatService(chatRepo, messageRepo)
+	if err := messageRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("Failed to create message indexes: %v", err)
+	}
+
+	// 메시지 검색 구현체 선택
+	var searcher search.Searcher
+	switch config.Search.Backend {
+	case "memory":
+		searcher = search.NewMemoryIndex()
+	case "", "mongo":
+		mongoSearcher := search.NewMongoSearcher(db)
+		if err := mongoSearcher.EnsureIndexes(); err != nil {
+			log.Fatalf("Failed to create search indexes: %v", err)
+		}
+		searcher = mongoSearcher
+	default:
+		log.Fatalf("Unknown search backend: %s", config.Search.Backend)
+	}
+
+	// 첨부파일 저장소 선택
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	if err := attachmentRepo.EnsureIndexes(); err != nil {
+		log.Fatalf("Failed to create attachment indexes: %v", err)
+	}
+	if err := config.Storage.ValidateSigningKey(); err != nil {
+		log.Fatalf("Invalid attachment storage config: %v", err)
+	}
+	var blobStore storage.Storage
+	switch config.Storage.Backend {
+	case "", "local":
+		blobStore, err = storage.NewLocalStorage(config.Storage.LocalDir)
+	case "s3":
+		blobStore, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:     config.Storage.S3Endpoint,
+			Region:       config.Storage.S3Region,
+			Bucket:       config.Storage.S3Bucket,
+			AccessKey:    config.Storage.S3AccessKey,
+			SecretKey:    config.Storage.S3SecretKey,
+			UsePathStyle: config.Storage.S3PathStyle,
+		})
+	default:
+		log.Fatalf("Unknown storage backend: %s", config.Storage.Backend)
+	}
+	if err != nil {
+		log.Fatalf("Failed to initialize attachment storage: %v", err)
+	}
+
+	// 이미지 첨부파일 처리 워커
+	imageService := services.NewImageService(attachmentRepo, blobStore, 100, 2)
+
+	chatService := services.NewChatService(chatRepo, messageRepo, attachmentRepo, wsManager, searcher, blobStore, imageService, services.ChatOptions{
+		EditWindow:              time.Duration(config.Chat.MessageEditWindow) * time.Second,
+		DeletedMessageRetention: time.Duration(config.Chat.DeletedMessageRetention) * time.Second,
+		MaxUploadSize:           config.Storage.MaxUploadSize,
+		DownloadURLTTL:          time.Duration(config.Storage.URLTTL) * time.Second,
+		DownloadSigningKey:      []byte(config.Storage.SigningKey),
+		UnclaimedAttachmentTTL:  time.Duration(config.Storage.UnclaimedTTL) * time.Second,
+	})
+	if config.Search.Backend == "memory" {
+		if err := chatService.ReindexSearch(); err != nil {
+			log.Fatalf("Failed to build search index: %v", err)
+		}
+	}
+	if err := imageService.ResumePending(); err != nil {
+		log.Printf("Failed to resume image processing: %v", err)
+	}
+	if err := chatService.ResumeRoomPurges(); err != nil {
+		log.Printf("Failed to resume room purges: %v", err)
+	}
+	chatService.StartAttachmentCleanup(time.Hour)
 	chatHandler := handlers.NewChatHandler(chatService)
 
 	// WebSocketService 초기화
-	wsService := services.NewWebSocketService(wsManager, messageRepo, chatRepo)
+	wsService := services.NewWebSocketService(wsManager, messageRepo, chatRepo, chatService)
 
 	// AuthMiddleware 초기화
-	authMiddleware := middleware.NewAuthMiddleware("access-secret-key")
+	authMiddleware := middleware.NewAuthMiddleware(authService)
 
 	// 라우터 설정
 	router := mux.NewRouter()
 	authHandler.RegisterRoutes(router) // 회원가입 및 인증 관련 라우트 추가
-	router.HandleFunc("/ws", websocket.WebSocketHandler(wsManager, wsService))
+	router.HandleFunc("/ws", websocket.WebSocketHandler(wsManager, wsService, authService))
+
+	// 인증이 필요한 계정 관련 API
+	accountRouter := router.NewRoute().Subrouter()
+	accountRouter.Use(authMiddleware.MiddlewareFunc)
+	authHandler.RegisterProtectedRoutes(accountRouter)
+
+	// 관리자 API (admin 역할만 허용)
+	adminService := services.NewAdminService(userRepo, authService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	adminRouter := router.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(authMiddleware.MiddlewareFunc, middleware.RequireRole(models.RoleAdmin))
+	adminHandler.RegisterRoutes(adminRouter)
 
 	// 채팅 관련 API에 미들웨어 적용
 	chatRouter := router.PathPrefix("/chat-rooms").Subrouter()
 	chatRouter.Use(authMiddleware.MiddlewareFunc)
 	chatHandler.RegisterRoutes(chatRouter)
 
+	// 초대 코드 API
+	inviteRouter := router.PathPrefix("/invites").Subrouter()
+	inviteRouter.Use(authMiddleware.MiddlewareFunc)
+	chatHandler.RegisterInviteRoutes(inviteRouter)
+
+	// 검색 API
+	searchRouter := router.PathPrefix("/search").Subrouter()
+	searchRouter.Use(authMiddleware.MiddlewareFunc)
+	chatHandler.RegisterSearchRoutes(searchRouter)
+
+	// 멘션 보관함 API
+	mentionRouter := router.PathPrefix("/mentions").Subrouter()
+	mentionRouter.Use(authMiddleware.MiddlewareFunc)
+	chatHandler.RegisterMentionRoutes(mentionRouter)
+
+	// 첨부파일 다운로드 API (서명된 URL로 인증)
+	attachmentRouter := router.PathPrefix("/attachments").Subrouter()
+	chatHandler.RegisterAttachmentRoutes(attachmentRouter)
+
 	// 서버 시작
 	fmt.Printf("Server starting on port %s\n", config.Server.Port)
 	log.Fatal(http.ListenAndServe(":"+config.Server.Port, router))
@@ -91,7 +211,34 @@ func overrideConfigWithEnv(config *utils.Config) {
 	if port := os.Getenv("SERVER_PORT"); port != "" {
 		config.Server.Port = port
 	}
+	if frontendURL := os.Getenv("FRONTEND_URL"); frontendURL != "" {
+		config.Server.FrontendURL = frontendURL
+	}
+	if proxies := os.Getenv("TRUSTED_PROXIES"); proxies != "" {
+		config.Server.TrustedProxies = strings.Split(proxies, ",")
+	}
 	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
 		config.Database.Url = dbURL
 	}
+	if editWindow, err := strconv.Atoi(os.Getenv("MESSAGE_EDIT_WINDOW")); err == nil {
+		config.Chat.MessageEditWindow = editWindow
+	}
+	if retention, err := strconv.Atoi(os.Getenv("DELETED_MESSAGE_RETENTION")); err == nil {
+		config.Chat.DeletedMessageRetention = retention
+	}
+	if backend := os.Getenv("SEARCH_BACKEND"); backend != "" {
+		config.Search.Backend = backend
+	}
+	if backend := os.Getenv("STORAGE_BACKEND"); backend != "" {
+		config.Storage.Backend = backend
+	}
+	if accessKey := os.Getenv("S3_ACCESS_KEY"); accessKey != "" {
+		config.Storage.S3AccessKey = accessKey
+	}
+	if secretKey := os.Getenv("S3_SECRET_KEY"); secretKey != "" {
+		config.Storage.S3SecretKey = secretKey
+	}
+	if signingKey := os.Getenv("ATTACHMENT_SIGNING_KEY"); signingKey != "" {
+		config.Storage.SigningKey = signingKey
+	}
 }
@@ -14,9 +14,10 @@ type BroadcastMessage struct {
 
 // RegisterMessage 연결 등록 구조체
 type RegisterMessage struct {
-	RoomID string
-	UserID string
-	Conn   *websocket.Conn
+	RoomID    string
+	UserID    string
+	SessionID string
+	Conn      *websocket.Conn
 }
 
 // UnregisterMessage 연결 해제 구조체
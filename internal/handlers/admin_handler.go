@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"chat-go-api/internal/middleware"
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/services"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+type AdminHandler struct {
+	adminService *services.AdminService
+}
+
+func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+// GetRolesHandler 역할별 권한 목록 조회
+func (h *AdminHandler) GetRolesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.RolePermissions)
+}
+
+// ListUsersHandler 사용자 목록 검색
+func (h *AdminHandler) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryLimit(r, 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := queryPage(r, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	users, total, err := h.adminService.ListUsers(r.URL.Query().Get("q"), limit, page)
+	if err != nil {
+		log.Printf("Failed to list users: %v", err)
+		http.Error(w, "failed to list users", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+		"users": users,
+	})
+}
+
+// GetUserHandler 사용자 상세 및 로그인 이력 조회
+func (h *AdminHandler) GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	detail, err := h.adminService.GetUserDetail(mux.Vars(r)["userID"])
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(detail)
+}
+
+// ChangeRoleHandler 사용자 역할 변경
+func (h *AdminHandler) ChangeRoleHandler(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	actorID, _ := r.Context().Value("user_id").(string)
+	if err := h.adminService.ChangeRole(actorID, mux.Vars(r)["userID"], req.Role); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmailHandler 이메일 인증 강제 처리
+func (h *AdminHandler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.VerifyEmail(mux.Vars(r)["userID"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DisableUserHandler 계정 비활성화
+func (h *AdminHandler) DisableUserHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, _ := r.Context().Value("user_id").(string)
+	if err := h.adminService.DisableUser(actorID, mux.Vars(r)["userID"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableUserHandler 계정 재활성화
+func (h *AdminHandler) EnableUserHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.EnableUser(mux.Vars(r)["userID"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForceLogoutHandler 사용자의 모든 세션 강제 종료
+func (h *AdminHandler) ForceLogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.ForceLogout(mux.Vars(r)["userID"]); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 서비스 에러를 HTTP 상태 코드로 변환
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidRole), errors.Is(err, services.ErrCannotTargetSelf):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("Admin operation failed: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc("/roles", h.GetRolesHandler).Methods("GET")
+
+	readRouter := router.NewRoute().Subrouter()
+	readRouter.Use(middleware.RequirePermission(models.PermissionReadUsers))
+	readRouter.HandleFunc("/users", h.ListUsersHandler).Methods("GET")
+	readRouter.HandleFunc("/users/{userID}", h.GetUserHandler).Methods("GET")
+
+	manageRouter := router.NewRoute().Subrouter()
+	manageRouter.Use(middleware.RequirePermission(models.PermissionManageUsers))
+	manageRouter.HandleFunc("/users/{userID}/role", h.ChangeRoleHandler).Methods("PATCH")
+	manageRouter.HandleFunc("/users/{userID}/verify-email", h.VerifyEmailHandler).Methods("POST")
+	manageRouter.HandleFunc("/users/{userID}/disable", h.DisableUserHandler).Methods("POST")
+	manageRouter.HandleFunc("/users/{userID}/enable", h.EnableUserHandler).Methods("POST")
+	manageRouter.HandleFunc("/users/{userID}/logout", h.ForceLogoutHandler).Methods("POST")
+}
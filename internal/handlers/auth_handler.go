@@ -3,17 +3,23 @@ package handlers
 import (
 	"chat-go-api/internal/services"
 	"encoding/json"
+	"errors"
+	"log"
+	"net"
 	"net/http"
+	"net/netip"
+	"strings"
 
 	"github.com/gorilla/mux"
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
+	authService    *services.AuthService
+	trustedProxies []netip.Prefix // X-Forwarded-For를 신뢰할 프록시 주소
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService *services.AuthService, trustedProxies []netip.Prefix) *AuthHandler {
+	return &AuthHandler{authService: authService, trustedProxies: trustedProxies}
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -26,7 +32,137 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, err := h.authService.Login(req.Email, req.Password)
+	result, err := h.authService.Login(req.Email, req.Password, r.UserAgent(), h.clientIP(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// 2단계 인증 로그인 핸들러
+func (h *AuthHandler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChallengeToken == "" || req.Code == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.authService.VerifyTwoFactorLogin(req.ChallengeToken, req.Code, r.UserAgent(), h.clientIP(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	writeLoginResult(w, result)
+}
+
+// 2단계 인증 등록 시작 핸들러
+func (h *AuthHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.authService.BeginTwoFactorEnrollment(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// 2단계 인증 등록 확인 핸들러
+func (h *AuthHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	recoveryCodes, err := h.authService.ConfirmTwoFactorEnrollment(userID, req.Code)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// 2단계 인증 해제 핸들러
+func (h *AuthHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.DisableTwoFactor(userID, req.Password, req.Code); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 로그인 결과 응답 (2단계 인증이 필요하면 챌린지 토큰만 반환)
+func writeLoginResult(w http.ResponseWriter, result *services.LoginResult) {
+	w.Header().Set("Content-Type", "application/json")
+	if result.ChallengeToken != "" {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"two_factor_required": true,
+			"challenge_token":     result.ChallengeToken,
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+	})
+}
+
+// 토큰 재발급 핸들러
+func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	accessToken, refreshToken, err := h.authService.RefreshToken(req.RefreshToken)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 		return
@@ -38,6 +174,78 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// 로그아웃 핸들러 (현재 세션)
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, ok := r.Context().Value("session_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.Logout(sessionID); err != nil {
+		http.Error(w, "failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 전체 세션 로그아웃 핸들러
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		http.Error(w, "failed to logout", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 활성 세션 목록 핸들러
+func (h *AuthHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	sessionID, _ := r.Context().Value("session_id").(string)
+
+	sessions, err := h.authService.GetSessions(userID, sessionID)
+	if err != nil {
+		http.Error(w, "failed to get sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+// 세션 폐기 핸들러
+func (h *AuthHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.authService.RevokeSession(userID, mux.Vars(r)["sessionID"])
+	if errors.Is(err, services.ErrSessionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to revoke session", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // 회원가입 핸들러
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -59,6 +267,44 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Registration successful. Check your email to verify your account."))
 }
 
+// 비밀번호 재설정 요청 핸들러
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Email == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	// 가입 여부가 드러나지 않도록 처리 중 오류가 나도 항상 같은 응답을 보낸다.
+	if err := h.authService.RequestPasswordReset(req.Email); err != nil {
+		log.Printf("Failed to process password reset request: %v", err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("If the email is registered, a password reset link has been sent."))
+}
+
+// 비밀번호 재설정 핸들러
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Token == "" {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.ResetPassword(req.Token, req.Password); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Write([]byte("Password reset successful"))
+}
+
 // 이메일 인증 핸들러
 func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
@@ -79,4 +325,59 @@ func (h *AuthHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("/register", h.Register).Methods("POST")
 	router.HandleFunc("/verify-email", h.VerifyEmail).Methods("GET")
 	router.HandleFunc("/login", h.Login).Methods("POST")
+	router.HandleFunc("/login/2fa", h.LoginTwoFactor).Methods("POST")
+	router.HandleFunc("/token/refresh", h.RefreshToken).Methods("POST")
+	router.HandleFunc("/password/forgot", h.ForgotPassword).Methods("POST")
+	router.HandleFunc("/password/reset", h.ResetPassword).Methods("POST")
+}
+
+// RegisterProtectedRoutes 인증이 필요한 라우트 등록
+func (h *AuthHandler) RegisterProtectedRoutes(router *mux.Router) {
+	router.HandleFunc("/logout", h.Logout).Methods("POST")
+	router.HandleFunc("/logout-all", h.LogoutAll).Methods("POST")
+	router.HandleFunc("/sessions", h.GetSessions).Methods("GET")
+	router.HandleFunc("/sessions/{sessionID}", h.RevokeSession).Methods("DELETE")
+	router.HandleFunc("/2fa/enroll", h.EnrollTwoFactor).Methods("POST")
+	router.HandleFunc("/2fa/confirm", h.ConfirmTwoFactor).Methods("POST")
+	router.HandleFunc("/2fa/disable", h.DisableTwoFactor).Methods("POST")
+}
+
+// 요청한 클라이언트의 IP 주소 (프록시 헤더 우선)
+func (h *AuthHandler) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !h.isTrustedProxy(host) {
+		return host
+	}
+
+	// 신뢰하는 프록시를 거쳐 온 요청이면 오른쪽부터 거슬러 올라가 처음 만나는 신뢰하지 않는 주소를 사용
+	// (왼쪽 값은 클라이언트가 임의로 넣을 수 있음)
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !h.isTrustedProxy(hop) {
+			return hop
+		}
+		host = hop
+	}
+	return host
+}
+
+func (h *AuthHandler) isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range h.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
 }
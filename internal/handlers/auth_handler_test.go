@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	handler := NewAuthHandler(nil, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{"direct request", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"spoofed header from untrusted peer", "203.0.113.7:5000", []string{"1.2.3.4"}, "203.0.113.7"},
+		{"trusted proxy", "10.0.0.2:5000", []string{"198.51.100.9"}, "198.51.100.9"},
+		{"spoofed prefix behind trusted proxy", "10.0.0.2:5000", []string{"1.2.3.4, 198.51.100.9"}, "198.51.100.9"},
+		{"proxy chain", "10.0.0.2:5000", []string{"198.51.100.9, 10.0.0.3"}, "198.51.100.9"},
+		{"multiple headers", "10.0.0.2:5000", []string{"1.2.3.4", "198.51.100.9"}, "198.51.100.9"},
+		{"trusted proxy without header", "10.0.0.2:5000", nil, "10.0.0.2"},
+		{"only trusted hops", "10.0.0.2:5000", []string{"10.0.0.5"}, "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := handler.clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
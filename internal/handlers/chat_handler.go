@@ -1,11 +1,19 @@
 package handlers
 
 import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
 	"chat-go-api/internal/services"
+	"chat-go-api/internal/utils"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime"
 	"net/http"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,8 +30,9 @@ func NewChatHandler(chatService *services.ChatService) *ChatHandler {
 // CreateChatRoomHandler 채팅방 생성
 func (h *ChatHandler) CreateChatRoomHandler(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Name      string   `json:"name"`
-		MemberIDs []string `json:"member_ids"`
+		Name       string   `json:"name"`
+		Visibility string   `json:"visibility"`
+		MemberIDs  []string `json:"member_ids"`
 	}
 
 	userID := r.Context().Value("user_id")
@@ -50,13 +59,48 @@ func (h *ChatHandler) CreateChatRoomHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	// 채팅방 생성
-	room, err := h.chatService.CreateChatRoom(req.Name, memberIDs)
+	room, err := h.chatService.CreateChatRoom(userID.(string), req.Name, req.Visibility, memberIDs)
+	if errors.Is(err, services.ErrInvalidRoomVisibility) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	roomDTO, err := h.chatService.ToChatRoomDTO(room, userID.(string))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// 응답
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(roomDTO)
+}
+
+// GetOrCreateDirectRoomHandler 1:1 대화방 조회 또는 생성
+func (h *ChatHandler) GetOrCreateDirectRoomHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	room, err := h.chatService.GetOrCreateDirectRoom(userID, req.UserID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(room)
 }
@@ -69,44 +113,113 @@ func (h *ChatHandler) GetUserChatRoomsHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	rooms, err := h.chatService.GetUserChatRooms(userID.(string))
+	params := r.URL.Query()
+	limit, err := queryInt64(r, "limit", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := services.RoomListQuery{
+		Type:     params.Get("type"),
+		Archived: params.Get("archived"),
+		Cursor:   params.Get("cursor"),
+		Limit:    limit,
+	}
+	if query.Archived == "" && params.Get("include_archived") == "true" {
+		query.Archived = repository.ArchivedInclude
+	}
+
+	// cursor, limit 없이 호출하는 기존 클라이언트에는 예전처럼 전체 목록을 배열로 반환
+	if !params.Has("cursor") && !params.Has("limit") {
+		rooms, err := h.chatService.GetAllUserChatRooms(userID.(string), query)
+		if errors.Is(err, services.ErrInvalidRoomListFilter) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to get chat rooms", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rooms)
+		return
+	}
+
+	rooms, nextCursor, err := h.chatService.GetUserChatRooms(userID.(string), query)
+	if errors.Is(err, services.ErrInvalidRoomListFilter) || errors.Is(err, utils.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to get chat rooms", http.StatusInternalServerError)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(rooms)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rooms":       rooms,
+		"next_cursor": nextCursor,
+	})
 }
 
 func (h *ChatHandler) GetChatRoomMessagesHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	roomID := vars["roomID"]
 
-	limitParam := r.URL.Query().Get("limit")
-	pageParam := r.URL.Query().Get("page")
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
 
-	limit := int64(20)
-	if limitParam != "" {
-		parsedLimit, err := strconv.ParseInt(limitParam, 10, 64)
-		if err != nil || parsedLimit <= 0 {
-			http.Error(w, "invalid limit", http.StatusBadRequest)
-			return
-		}
-		limit = parsedLimit
+	// 채팅방 접근 권한 확인
+	if _, err := h.chatService.AuthorizeRoomAccess(roomID, userID); err != nil {
+		writeRoomError(w, err)
+		return
 	}
 
-	page := int64(1)
-	if pageParam != "" {
-		parsedPage, err := strconv.ParseInt(pageParam, 10, 64)
-		if err != nil || parsedPage <= 0 {
-			http.Error(w, "invalid page", http.StatusBadRequest)
+	limit, err := queryLimit(r, 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := queryPage(r, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 스레드 답글을 타임라인에서 숨길지 여부
+	hideReplies := r.URL.Query().Get("hide_replies") == "true"
+
+	// before/after/around 중 하나가 있으면 커서 기반으로 조회 (값이 빈 before=는 최신 메시지부터)
+	// 없으면 기존 클라이언트를 위해 page 기반으로 조회
+	params := r.URL.Query()
+	if params.Has("before") || params.Has("after") || params.Has("around") {
+		query := services.MessageCursorQuery{
+			Before:      params.Get("before"),
+			After:       params.Get("after"),
+			Around:      params.Get("around"),
+			Limit:       limit,
+			HideReplies: hideReplies,
+		}
+		messagePage, err := h.chatService.GetMessagesByCursor(roomID, userID, query)
+		if errors.Is(err, utils.ErrInvalidCursor) || errors.Is(err, services.ErrInvalidMessageQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		page = parsedPage
+		if err != nil {
+			writeRoomError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(messagePage)
+		return
 	}
 
-	messages, err := h.chatService.GetRecentMessages(roomID, limit, page)
+	messages, err := h.chatService.GetRecentMessages(roomID, limit, page, hideReplies)
 	if err != nil {
 		log.Printf("Failed to get messages: %v", err)
 		http.Error(w, "failed to retrieve messages", http.StatusInternalServerError)
@@ -114,7 +227,7 @@ func (h *ChatHandler) GetChatRoomMessagesHandler(w http.ResponseWriter, r *http.
 	}
 
 	// 메시지 총 개수 가져오기
-	totalMessages, err := h.chatService.GetTotalMessagesCount(roomID)
+	totalMessages, err := h.chatService.GetTotalMessagesCount(roomID, hideReplies)
 	if err != nil {
 		log.Printf("Failed to get total message count: %v", err)
 		http.Error(w, "failed to retrieve total message count", http.StatusInternalServerError)
@@ -133,8 +246,840 @@ func (h *ChatHandler) GetChatRoomMessagesHandler(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetThreadRepliesHandler 스레드 답글 조회
+func (h *ChatHandler) GetThreadRepliesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryLimit(r, 50)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := queryPage(r, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+	thread, err := h.chatService.GetThreadReplies(vars["roomID"], userID, vars["messageID"], limit, page)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(thread)
+}
+
+// EditMessageHandler 메시지 수정
+func (h *ChatHandler) EditMessageHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+	message, err := h.chatService.EditMessage(vars["roomID"], userID, vars["messageID"], req.Content)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(message)
+}
+
+// DeleteMessageHandler 메시지 삭제 (작성자 또는 방장/관리자)
+func (h *ChatHandler) DeleteMessageHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if _, err := h.chatService.DeleteMessage(vars["roomID"], userID, vars["messageID"]); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddReactionHandler 메시지에 리액션 추가
+func (h *ChatHandler) AddReactionHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, true)
+}
+
+// RemoveReactionHandler 메시지에서 리액션 제거
+func (h *ChatHandler) RemoveReactionHandler(w http.ResponseWriter, r *http.Request) {
+	h.changeReaction(w, r, false)
+}
+
+func (h *ChatHandler) changeReaction(w http.ResponseWriter, r *http.Request, add bool) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	var reaction *models.ReactionDTO
+	var err error
+	if add {
+		reaction, err = h.chatService.AddReaction(vars["roomID"], userID, vars["messageID"], vars["emoji"])
+	} else {
+		reaction, err = h.chatService.RemoveReaction(vars["roomID"], userID, vars["messageID"], vars["emoji"])
+	}
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reaction)
+}
+
+// GetMessageEditsHandler 메시지 수정 이력 조회
+func (h *ChatHandler) GetMessageEditsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	edits, err := h.chatService.GetMessageEdits(vars["roomID"], userID, vars["messageID"])
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(edits)
+}
+
+// MarkRoomReadHandler 채팅방 읽음 처리 (message_id가 없으면 최신 메시지까지)
+func (h *ChatHandler) MarkRoomReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		MessageID string `json:"message_id"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	receipt, err := h.chatService.MarkRoomRead(mux.Vars(r)["roomID"], userID, req.MessageID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipt)
+}
+
+// GetReadReceiptsHandler 채팅방 멤버들의 읽음 위치 조회
+func (h *ChatHandler) GetReadReceiptsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	receipts, err := h.chatService.GetReadReceipts(mux.Vars(r)["roomID"], userID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(receipts)
+}
+
+// AddMembersHandler 채팅방 멤버 추가
+func (h *ChatHandler) AddMembersHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.UserIDs) == 0 {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	memberIDs := []primitive.ObjectID{}
+	for _, id := range req.UserIDs {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			http.Error(w, "Invalid member ID", http.StatusBadRequest)
+			return
+		}
+		memberIDs = append(memberIDs, objID)
+	}
+
+	if err := h.chatService.AddMembers(mux.Vars(r)["roomID"], userID, memberIDs); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMemberHandler 채팅방 멤버 내보내기
+func (h *ChatHandler) RemoveMemberHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.chatService.RemoveMember(vars["roomID"], userID, vars["userID"]); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetMemberRoleHandler 멤버 역할 변경 (admin/member)
+func (h *ChatHandler) SetMemberRoleHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.chatService.SetMemberRole(vars["roomID"], userID, vars["userID"], req.Role); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LeaveRoomHandler 채팅방 나가기
+func (h *ChatHandler) LeaveRoomHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.chatService.LeaveRoom(mux.Vars(r)["roomID"], userID); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TransferOwnershipHandler 방장 위임
+func (h *ChatHandler) TransferOwnershipHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.UserID == "" {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.chatService.TransferOwnership(mux.Vars(r)["roomID"], userID, req.UserID); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ArchiveRoomHandler 채팅방 보관
+func (h *ChatHandler) ArchiveRoomHandler(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, true)
+}
+
+// UnarchiveRoomHandler 채팅방 보관 해제
+func (h *ChatHandler) UnarchiveRoomHandler(w http.ResponseWriter, r *http.Request) {
+	h.setArchived(w, r, false)
+}
+
+func (h *ChatHandler) setArchived(w http.ResponseWriter, r *http.Request, archive bool) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	roomID := mux.Vars(r)["roomID"]
+	var room *models.ChatRoomDTO
+	var err error
+	if archive {
+		room, err = h.chatService.ArchiveRoom(roomID, userID)
+	} else {
+		room, err = h.chatService.UnarchiveRoom(roomID, userID)
+	}
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room)
+}
+
+// DeleteRoomHandler 채팅방 영구 삭제
+func (h *ChatHandler) DeleteRoomHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.chatService.DeleteRoom(mux.Vars(r)["roomID"], userID); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateChatRoomHandler 채팅방 메타데이터 수정
+func (h *ChatHandler) UpdateChatRoomHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Name        *string `json:"name"`
+		Topic       *string `json:"topic"`
+		Description *string `json:"description"`
+		AvatarURL   *string `json:"avatar_url"`
+		Visibility  *string `json:"visibility"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	room, err := h.chatService.UpdateRoomMetadata(mux.Vars(r)["roomID"], userID, services.RoomMetadataUpdate{
+		Name:        req.Name,
+		Topic:       req.Topic,
+		Description: req.Description,
+		AvatarURL:   req.AvatarURL,
+		Visibility:  req.Visibility,
+	})
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room)
+}
+
+// SearchPublicRoomsHandler 공개 채팅방 디렉터리 검색
+func (h *ChatHandler) SearchPublicRoomsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryLimit(r, 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := queryPage(r, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rooms, total, err := h.chatService.SearchPublicRooms(userID, r.URL.Query().Get("q"), limit, page)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+		"rooms": rooms,
+	})
+}
+
+// JoinRoomHandler 공개 채팅방 참여 또는 참여 요청
+func (h *ChatHandler) JoinRoomHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.chatService.JoinRoom(mux.Vars(r)["roomID"], userID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Joined {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetJoinRequestsHandler 대기 중인 참여 요청 목록
+func (h *ChatHandler) GetJoinRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	requests, err := h.chatService.GetJoinRequests(mux.Vars(r)["roomID"], userID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// ApproveJoinRequestHandler 참여 요청 승인
+func (h *ChatHandler) ApproveJoinRequestHandler(w http.ResponseWriter, r *http.Request) {
+	h.decideJoinRequest(w, r, true)
+}
+
+// DenyJoinRequestHandler 참여 요청 거절
+func (h *ChatHandler) DenyJoinRequestHandler(w http.ResponseWriter, r *http.Request) {
+	h.decideJoinRequest(w, r, false)
+}
+
+func (h *ChatHandler) decideJoinRequest(w http.ResponseWriter, r *http.Request, approve bool) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	request, err := h.chatService.DecideJoinRequest(vars["roomID"], userID, vars["requestID"], approve)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(request)
+}
+
+// CreateInviteHandler 초대 코드 생성
+func (h *ChatHandler) CreateInviteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req struct {
+		Role             string `json:"role"`
+		MaxUses          int    `json:"max_uses"`
+		ExpiresInSeconds int64  `json:"expires_in_seconds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	invite, err := h.chatService.CreateInvite(mux.Vars(r)["roomID"], userID, services.InviteOptions{
+		Role:      req.Role,
+		MaxUses:   req.MaxUses,
+		ExpiresIn: time.Duration(req.ExpiresInSeconds) * time.Second,
+	})
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(invite)
+}
+
+// GetInvitesHandler 초대 코드 목록
+func (h *ChatHandler) GetInvitesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	invites, err := h.chatService.GetInvites(mux.Vars(r)["roomID"], userID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invites)
+}
+
+// RevokeInviteHandler 초대 코드 폐기
+func (h *ChatHandler) RevokeInviteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	if err := h.chatService.RevokeInvite(vars["roomID"], userID, vars["code"]); err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AcceptInviteHandler 초대 코드로 채팅방 참여
+func (h *ChatHandler) AcceptInviteHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	room, err := h.chatService.AcceptInvite(mux.Vars(r)["code"], userID)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(room)
+}
+
+// SearchMessagesHandler 접근 가능한 채팅방의 메시지 검색
+// 예: /search/messages?q=배포 from:me in:general after:2024-01-01 has:attachment
+func (h *ChatHandler) SearchMessagesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryLimit(r, 20)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := queryPage(r, limit)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.chatService.SearchMessages(userID, r.URL.Query().Get("q"), limit, page)
+	if errors.Is(err, services.ErrInvalidSearchQuery) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to search messages: %v", err)
+		http.Error(w, "failed to search messages", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetMentionsHandler 멘션 보관함 조회 (?unread=true로 읽지 않은 멘션만)
+func (h *ChatHandler) GetMentionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := queryInt64(r, "limit", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := h.chatService.GetMentions(userID, services.MentionQuery{
+		UnreadOnly: r.URL.Query().Get("unread") == "true",
+		Cursor:     r.URL.Query().Get("cursor"),
+		Limit:      limit,
+	})
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to get mentions: %v", err)
+		http.Error(w, "Failed to get mentions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// MarkMentionReadHandler 멘션 하나를 읽음 처리
+func (h *ChatHandler) MarkMentionReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	err := h.chatService.MarkMentionRead(userID, mux.Vars(r)["mentionID"])
+	if errors.Is(err, services.ErrMentionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to mark mention as read: %v", err)
+		http.Error(w, "Failed to mark mention as read", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllMentionsReadHandler 멘션 보관함 전체 읽음 처리
+func (h *ChatHandler) MarkAllMentionsReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.chatService.MarkAllMentionsRead(userID); err != nil {
+		log.Printf("Failed to mark mentions as read: %v", err)
+		http.Error(w, "Failed to mark mentions as read", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// 첨부파일 업로드 요청에서 파일 외 multipart 오버헤드로 허용하는 크기
+const multipartOverhead = 1 << 20
+
+// UploadAttachmentHandler 첨부파일 업로드 (multipart/form-data, 필드 이름 file)
+func (h *ChatHandler) UploadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if maxSize := h.chatService.MaxUploadSize(); maxSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, maxSize+multipartOverhead)
+	}
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeRoomError(w, services.ErrAttachmentTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.chatService.UploadAttachment(mux.Vars(r)["roomID"], userID, header.Filename, file, header.Size)
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// GetAttachmentURLHandler 첨부파일 다운로드 URL 발급 (?variant=small 등으로 썸네일 지정)
+func (h *ChatHandler) GetAttachmentURLHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value("user_id").(string)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vars := mux.Vars(r)
+	url, err := h.chatService.GetAttachmentURL(vars["roomID"], userID, vars["attachmentID"], r.URL.Query().Get("variant"))
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(url)
+}
+
+// DownloadAttachmentHandler 서명된 URL로 첨부파일 다운로드
+func (h *ChatHandler) DownloadAttachmentHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	content, err := h.chatService.OpenAttachment(mux.Vars(r)["attachmentID"], query.Get("variant"), query.Get("expires"), query.Get("signature"))
+	if err != nil {
+		writeRoomError(w, err)
+		return
+	}
+	defer content.Body.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(content.ContentType, "image/") {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Type", content.ContentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", content.Size))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": content.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	if _, err := io.Copy(w, content.Body); err != nil {
+		log.Printf("Failed to stream attachment %s: %v", content.ID.Hex(), err)
+	}
+}
+
+// 채팅방 관련 서비스 에러를 HTTP 상태 코드로 변환
+func writeRoomError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoomNotFound), errors.Is(err, services.ErrJoinRequestNotFound),
+		errors.Is(err, services.ErrInviteNotFound), errors.Is(err, services.ErrMessageNotFound),
+		errors.Is(err, services.ErrReactionNotFound), errors.Is(err, services.ErrAttachmentNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, services.ErrRoomAccessDenied), errors.Is(err, services.ErrRoomPermissionDenied),
+		errors.Is(err, services.ErrMessageEditForbidden), errors.Is(err, services.ErrMessageEditWindowExpired),
+		errors.Is(err, services.ErrMessageDeleteForbidden), errors.Is(err, services.ErrInvalidDownloadLink):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrNotRoomMember),
+		errors.Is(err, services.ErrInvalidRoomRole), errors.Is(err, services.ErrInvalidDirectPeer),
+		errors.Is(err, services.ErrDirectRoomMembership), errors.Is(err, services.ErrDirectRoomNotEditable),
+		errors.Is(err, services.ErrInvalidRoomMetadata), errors.Is(err, services.ErrInvalidRoomVisibility),
+		errors.Is(err, services.ErrInvalidInvite), errors.Is(err, services.ErrInvalidThreadParent),
+		errors.Is(err, services.ErrInvalidMessageContent), errors.Is(err, services.ErrInvalidReaction),
+		errors.Is(err, services.ErrInvalidAttachment):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrUnsupportedImage):
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+	case errors.Is(err, services.ErrOwnerCannotLeave), errors.Is(err, services.ErrAlreadyRoomMember),
+		errors.Is(err, services.ErrRoomArchived), errors.Is(err, services.ErrReactionExists),
+		errors.Is(err, services.ErrAttachmentProcessing), errors.Is(err, services.ErrJoinRequestClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrInviteInvalid), errors.Is(err, services.ErrMessageDeleted),
+		errors.Is(err, services.ErrAttachmentUnavailable):
+		http.Error(w, err.Error(), http.StatusGone)
+	case errors.Is(err, services.ErrImageQueueFull):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		log.Printf("Chat room operation failed: %v", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
 func (h *ChatHandler) RegisterRoutes(router *mux.Router) {
 	router.HandleFunc("", h.GetUserChatRoomsHandler).Methods("GET")
 	router.HandleFunc("", h.CreateChatRoomHandler).Methods("POST")
+	router.HandleFunc("/direct", h.GetOrCreateDirectRoomHandler).Methods("POST")
+	router.HandleFunc("/public", h.SearchPublicRoomsHandler).Methods("GET")
+	router.HandleFunc("/{roomID}", h.UpdateChatRoomHandler).Methods("PATCH")
+	router.HandleFunc("/{roomID}", h.DeleteRoomHandler).Methods("DELETE")
+	router.HandleFunc("/{roomID}/archive", h.ArchiveRoomHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/unarchive", h.UnarchiveRoomHandler).Methods("POST")
 	router.HandleFunc("/{roomID}/messages", h.GetChatRoomMessagesHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/messages/{messageID}", h.EditMessageHandler).Methods("PATCH")
+	router.HandleFunc("/{roomID}/messages/{messageID}", h.DeleteMessageHandler).Methods("DELETE")
+	router.HandleFunc("/{roomID}/messages/{messageID}/edits", h.GetMessageEditsHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/messages/{messageID}/reactions/{emoji}", h.AddReactionHandler).Methods("PUT")
+	router.HandleFunc("/{roomID}/messages/{messageID}/reactions/{emoji}", h.RemoveReactionHandler).Methods("DELETE")
+	router.HandleFunc("/{roomID}/messages/{messageID}/thread", h.GetThreadRepliesHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/attachments", h.UploadAttachmentHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/attachments/{attachmentID}/url", h.GetAttachmentURLHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/read", h.MarkRoomReadHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/read-receipts", h.GetReadReceiptsHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/members", h.AddMembersHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/members/{userID}", h.RemoveMemberHandler).Methods("DELETE")
+	router.HandleFunc("/{roomID}/members/{userID}/role", h.SetMemberRoleHandler).Methods("PUT")
+	router.HandleFunc("/{roomID}/leave", h.LeaveRoomHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/join", h.JoinRoomHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/join-requests", h.GetJoinRequestsHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/join-requests/{requestID}/approve", h.ApproveJoinRequestHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/join-requests/{requestID}/deny", h.DenyJoinRequestHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/transfer-ownership", h.TransferOwnershipHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/invites", h.CreateInviteHandler).Methods("POST")
+	router.HandleFunc("/{roomID}/invites", h.GetInvitesHandler).Methods("GET")
+	router.HandleFunc("/{roomID}/invites/{code}", h.RevokeInviteHandler).Methods("DELETE")
+}
+
+// RegisterInviteRoutes 초대 코드 사용 라우트 등록 (/invites)
+func (h *ChatHandler) RegisterInviteRoutes(router *mux.Router) {
+	router.HandleFunc("/{code}/accept", h.AcceptInviteHandler).Methods("POST")
+}
+
+// RegisterSearchRoutes 검색 라우트 등록 (/search)
+func (h *ChatHandler) RegisterSearchRoutes(router *mux.Router) {
+	router.HandleFunc("/messages", h.SearchMessagesHandler).Methods("GET")
+}
+
+// RegisterAttachmentRoutes 첨부파일 다운로드 라우트 등록 (/attachments, 서명된 URL로 인증)
+func (h *ChatHandler) RegisterAttachmentRoutes(router *mux.Router) {
+	router.HandleFunc("/{attachmentID}/download", h.DownloadAttachmentHandler).Methods("GET")
+}
+
+// RegisterMentionRoutes 멘션 보관함 라우트 등록 (/mentions)
+func (h *ChatHandler) RegisterMentionRoutes(router *mux.Router) {
+	router.HandleFunc("", h.GetMentionsHandler).Methods("GET")
+	router.HandleFunc("/read", h.MarkAllMentionsReadHandler).Methods("POST")
+	router.HandleFunc("/{mentionID}/read", h.MarkMentionReadHandler).Methods("POST")
 }
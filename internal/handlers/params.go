@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// queryInt64 양의 정수 쿼리 파라미터 파싱 (없으면 기본값)
+func queryInt64(r *http.Request, name string, defaultValue int64) (int64, error) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return value, nil
+}
+
+// maxPageLimit 목록 조회 한 번에 반환할 최대 개수
+const maxPageLimit = 100
+
+// queryLimit limit 쿼리 파라미터 파싱 (없으면 기본값, maxPageLimit보다 크면 maxPageLimit)
+func queryLimit(r *http.Request, defaultValue int64) (int64, error) {
+	limit, err := queryInt64(r, "limit", defaultValue)
+	if err != nil {
+		return 0, err
+	}
+	return min(limit, maxPageLimit), nil
+}
+
+// queryPage page 쿼리 파라미터 파싱 (없으면 1)
+// 건너뛸 개수 (page-1)*limit가 int64를 넘는 값은 거부한다.
+func queryPage(r *http.Request, limit int64) (int64, error) {
+	page, err := queryInt64(r, "page", 1)
+	if err != nil {
+		return 0, err
+	}
+	if page-1 > math.MaxInt64/limit {
+		return 0, fmt.Errorf("invalid page")
+	}
+	return page, nil
+}
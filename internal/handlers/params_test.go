@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryLimit(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 20, false},
+		{"limit=5", 5, false},
+		{"limit=1000", maxPageLimit, false},
+		{"limit=0", 0, true},
+		{"limit=abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/?"+tt.query, nil)
+		got, err := queryLimit(r, 20)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("queryLimit(%q) = %d, %v; want %d, error %v", tt.query, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"page=3", 3, false},
+		{"page=461168601842738790", 461168601842738790, false}, // (page-1)*20은 int64 범위 안
+		{"page=9223372036854775807", 0, true},
+		{"page=-1", 0, true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/?"+tt.query, nil)
+		got, err := queryPage(r, 20)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("queryPage(%q) = %d, %v; want %d, error %v", tt.query, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	_ "image/gif" // GIF 디코더 등록
+	"image/jpeg"
+	"image/png"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrImageTooLarge     = errors.New("image dimensions are too large")
+	ErrCorruptImage      = errors.New("corrupt image data")
+)
+
+const (
+	MaxPixels          = 24_000_000 // 디코딩할 최대 픽셀 수 (압축 폭탄 방지, RGBA 약 96MB)
+	placeholderSize    = 16         // 블러 미리보기 최대 변 길이
+	thumbnailQuality   = 82
+	placeholderQuality = 50
+	reencodeQuality    = 92
+)
+
+// VariantSpec 생성할 썸네일 (긴 변 기준 최대 크기)
+type VariantSpec struct {
+	Name    string
+	MaxSize int
+}
+
+// Variant 생성된 썸네일
+type Variant struct {
+	Name        string
+	Width       int
+	Height      int
+	ContentType string
+	Data        []byte
+}
+
+// Result 이미지 처리 결과
+type Result struct {
+	Original    []byte // 메타데이터를 제거한 원본
+	ContentType string
+	Width       int // 회전 방향을 적용한 크기
+	Height      int
+	Variants    []Variant
+	Placeholder string // 클라이언트가 확대해 흐리게 표시하는 저해상도 미리보기 (data URI)
+}
+
+// IsSupported 처리할 수 있는 이미지 형식인지 확인
+func IsSupported(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// heifBrands HEIF 계열(HEIC, AVIF) 파일의 ftyp 브랜드
+var heifBrands = map[string]bool{
+	"heic": true, "heix": true, "hevc": true, "hevx": true, "heim": true, "heis": true,
+	"mif1": true, "msf1": true, "avif": true, "avis": true,
+}
+
+// IsUnsupportedImage 메타데이터를 제거할 수 없는 이미지인지 확인
+// 처리할 수 없는 image/* 형식(WebP, BMP 등)과 MIME 판별에서 빠지는 HEIC, AVIF, TIFF는
+// EXIF(GPS 포함)가 그대로 남으므로 첨부파일로 받지 않는다.
+func IsUnsupportedImage(contentType string, head []byte) bool {
+	if strings.HasPrefix(contentType, "image/") {
+		return !IsSupported(contentType)
+	}
+	if bytes.HasPrefix(head, []byte("II*\x00")) || bytes.HasPrefix(head, []byte("MM\x00*")) {
+		return true // TIFF (DNG 등 RAW 포함)
+	}
+	return len(head) >= 12 && string(head[4:8]) == "ftyp" && heifBrands[string(head[8:12])]
+}
+
+// Process 원본의 EXIF 등 메타데이터를 제거하고 썸네일과 블러 미리보기 생성
+// EXIF 회전 방향은 메타데이터를 지우기 전에 픽셀에 반영한다.
+// 원본보다 큰 썸네일은 만들지 않는다.
+func Process(data []byte, specs []VariantSpec) (*Result, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorruptImage
+	}
+	orientation := 1
+	if format == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	img := toRGBA(decoded, orientation)
+	defer releaseRGBA(img)
+
+	result := &Result{}
+	switch format {
+	case "jpeg":
+		result.ContentType = "image/jpeg"
+		if orientation > 1 {
+			// 메타데이터 없이도 같은 방향으로 보이도록 회전해서 다시 인코딩
+			result.Original, err = encode(img, "image/jpeg", reencodeQuality)
+		} else {
+			result.Original, err = StripJPEGMetadata(data)
+		}
+	case "png":
+		result.ContentType = "image/png"
+		result.Original, err = StripPNGMetadata(data)
+	case "gif":
+		// GIF에는 EXIF가 없고, 다시 인코딩하면 애니메이션이 사라지므로 그대로 둔다.
+		result.ContentType = "image/gif"
+		result.Original = data
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := img.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+
+	// 투명한 픽셀이 있으면 PNG, 아니면 JPEG로 썸네일 생성
+	contentType := "image/jpeg"
+	if !img.Opaque() {
+		contentType = "image/png"
+	}
+
+	// 큰 썸네일부터 만들고 작은 썸네일은 바로 앞의 썸네일을 줄여서 만듦 (원본은 한 번만 읽음)
+	order := make([]int, len(specs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return specs[order[a]].MaxSize > specs[order[b]].MaxSize })
+
+	var z resizer
+	source := img
+	variants := make([]*Variant, len(specs))
+	for _, i := range order {
+		spec := specs[i]
+		if max(result.Width, result.Height) <= spec.MaxSize {
+			continue
+		}
+		thumbnail := z.resize(source, fit(result.Width, result.Height, spec.MaxSize))
+		data, err := encode(thumbnail, contentType, thumbnailQuality)
+		if err != nil {
+			return nil, err
+		}
+		variants[i] = &Variant{
+			Name:        spec.Name,
+			Width:       thumbnail.Bounds().Dx(),
+			Height:      thumbnail.Bounds().Dy(),
+			ContentType: contentType,
+			Data:        data,
+		}
+		source = thumbnail
+	}
+	for _, variant := range variants {
+		if variant != nil {
+			result.Variants = append(result.Variants, *variant)
+		}
+	}
+
+	placeholder, err := encode(z.resize(source, fit(result.Width, result.Height, placeholderSize)), contentType, placeholderQuality)
+	if err != nil {
+		return nil, err
+	}
+	result.Placeholder = "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(placeholder)
+	return result, nil
+}
+
+// 가로세로 비율을 유지하며 긴 변이 maxSize가 되는 크기
+func fit(width, height, maxSize int) image.Point {
+	if width >= height {
+		return image.Pt(maxSize, max(1, (height*maxSize+width/2)/width))
+	}
+	return image.Pt(max(1, (width*maxSize+height/2)/height), maxSize)
+}
+
+func encode(img image.Image, contentType string, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == "image/png" {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,434 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// 위치마다 색이 다른 테스트 이미지
+func gradient(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{R: uint8(x * 255 / max(1, width-1)), G: uint8(y * 255 / max(1, height-1)), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// 회전 방향과 GPS 좌표가 들어 있는 EXIF(APP1) 세그먼트 (빅 엔디언 TIFF)
+func exifSegment(orientation uint16) []byte {
+	var tiff bytes.Buffer
+	tiff.WriteString("MM\x00\x2a")
+	binary.Write(&tiff, binary.BigEndian, uint32(8)) // IFD0 위치
+
+	// IFD0: Orientation, GPSInfo 포인터
+	binary.Write(&tiff, binary.BigEndian, uint16(2))
+	binary.Write(&tiff, binary.BigEndian, []uint16{exifOrientationTag, 3})
+	binary.Write(&tiff, binary.BigEndian, uint32(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{orientation, 0})
+	gpsOffset := uint32(8 + 2 + 2*12 + 4)
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x8825, 4})
+	binary.Write(&tiff, binary.BigEndian, []uint32{1, gpsOffset})
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	// GPS IFD: GPSLatitudeRef = "N"
+	binary.Write(&tiff, binary.BigEndian, uint16(1))
+	binary.Write(&tiff, binary.BigEndian, []uint16{0x0001, 2})
+	binary.Write(&tiff, binary.BigEndian, uint32(2))
+	tiff.WriteString("N\x00\x00\x00")
+	binary.Write(&tiff, binary.BigEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, markerAPP1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// SOI 바로 뒤에 세그먼트 삽입
+func insertJPEGSegments(data []byte, segments ...[]byte) []byte {
+	out := append([]byte{}, data[:2]...)
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return append(out, data[2:]...)
+}
+
+func commentSegment(text string) []byte {
+	segment := []byte{0xFF, markerCOM, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(text)+2))
+	return append(segment, text...)
+}
+
+// SOS 전까지의 JPEG 마커 목록
+func jpegMarkers(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var markers []byte
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			t.Fatalf("invalid marker at %d", i)
+		}
+		marker := data[i+1]
+		markers = append(markers, marker)
+		if marker == markerSOS {
+			break
+		}
+		i += 2 + int(binary.BigEndian.Uint16(data[i+2:]))
+	}
+	return markers
+}
+
+func assertNoJPEGMetadata(t *testing.T, name string, data []byte) {
+	t.Helper()
+	for _, marker := range jpegMarkers(t, data) {
+		if marker == markerAPP1 || marker == markerCOM {
+			t.Errorf("%s still has marker 0x%X", name, marker)
+		}
+	}
+	if bytes.Contains(data, []byte("Exif\x00\x00")) {
+		t.Errorf("%s still contains EXIF data", name)
+	}
+}
+
+func TestStripJPEGMetadataRemovesGPSExif(t *testing.T) {
+	original := encodeJPEG(t, gradient(32, 24))
+	withExif := insertJPEGSegments(original, exifSegment(1), commentSegment("taken at home"))
+	withExif = append(withExif, []byte("trailing data")...)
+
+	if got := jpegMarkers(t, withExif); got[0] != markerAPP1 {
+		t.Fatalf("fixture should start with APP1, got markers %X", got)
+	}
+
+	stripped, err := StripJPEGMetadata(withExif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoJPEGMetadata(t, "stripped", stripped)
+	if !bytes.Equal(stripped, original) {
+		t.Errorf("stripped JPEG differs from the original encoding (len %d, want %d)", len(stripped), len(original))
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("stripped JPEG does not decode: %v", err)
+	}
+}
+
+func TestStripJPEGMetadataRejectsCorruptData(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("not a jpeg"), {0xFF, 0xD8, 0xFF, 0xE1, 0xFF}} {
+		if _, err := StripJPEGMetadata(data); !errors.Is(err, ErrCorruptImage) {
+			t.Errorf("StripJPEGMetadata(%q): got %v, want ErrCorruptImage", data, err)
+		}
+	}
+}
+
+// PNG 청크 추가 (IEND 앞)
+func insertPNGChunk(data []byte, chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk, uint32(len(payload)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, payload...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+
+	iend := len(data) - 12
+	out := append([]byte{}, data[:iend]...)
+	out = append(out, chunk...)
+	return append(out, data[iend:]...)
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	original := encodePNG(t, gradient(8, 8))
+	withMetadata := insertPNGChunk(original, "tEXt", []byte("Comment\x00secret"))
+	withMetadata = insertPNGChunk(withMetadata, "eXIf", exifSegment(1)[10:])
+
+	stripped, err := StripPNGMetadata(withMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(stripped, original) {
+		t.Errorf("stripped PNG differs from the original encoding")
+	}
+}
+
+func TestToRGBAOrientation(t *testing.T) {
+	// 3x2 원본의 픽셀을 (x, y)로 표시
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			src.SetRGBA(x, y, color.RGBA{R: uint8(x), G: uint8(y), A: 255})
+		}
+	}
+
+	// 회전 후 각 줄에 오는 원본 좌표
+	tests := []struct {
+		orientation int
+		want        [][][2]int
+	}{
+		{1, [][][2]int{{{0, 0}, {1, 0}, {2, 0}}, {{0, 1}, {1, 1}, {2, 1}}}},
+		{2, [][][2]int{{{2, 0}, {1, 0}, {0, 0}}, {{2, 1}, {1, 1}, {0, 1}}}},
+		{3, [][][2]int{{{2, 1}, {1, 1}, {0, 1}}, {{2, 0}, {1, 0}, {0, 0}}}},
+		{4, [][][2]int{{{0, 1}, {1, 1}, {2, 1}}, {{0, 0}, {1, 0}, {2, 0}}}},
+		{5, [][][2]int{{{0, 0}, {0, 1}}, {{1, 0}, {1, 1}}, {{2, 0}, {2, 1}}}},
+		{6, [][][2]int{{{0, 1}, {0, 0}}, {{1, 1}, {1, 0}}, {{2, 1}, {2, 0}}}},
+		{7, [][][2]int{{{2, 1}, {2, 0}}, {{1, 1}, {1, 0}}, {{0, 1}, {0, 0}}}},
+		{8, [][][2]int{{{2, 0}, {2, 1}}, {{1, 0}, {1, 1}}, {{0, 0}, {0, 1}}}},
+	}
+
+	for _, tt := range tests {
+		dst := toRGBA(src, tt.orientation)
+		if dst.Bounds().Dy() != len(tt.want) || dst.Bounds().Dx() != len(tt.want[0]) {
+			t.Fatalf("orientation %d: size %v", tt.orientation, dst.Bounds())
+		}
+		for y, row := range tt.want {
+			for x, from := range row {
+				got := dst.RGBAAt(x, y)
+				if int(got.R) != from[0] || int(got.G) != from[1] {
+					t.Errorf("orientation %d: (%d,%d) = src(%d,%d), want src(%d,%d)", tt.orientation, x, y, got.R, got.G, from[0], from[1])
+				}
+			}
+		}
+		releaseRGBA(dst)
+	}
+}
+
+func TestResize(t *testing.T) {
+	var z resizer
+
+	t.Run("averages blocks", func(t *testing.T) {
+		// 2x2 블록마다 흰색과 검은색이 반씩 섞인 체크 무늬
+		src := image.NewRGBA(image.Rect(0, 0, 4, 4))
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				if (x+y)%2 == 0 {
+					src.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+				} else {
+					src.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+				}
+			}
+		}
+		dst := z.resize(src, image.Pt(2, 2))
+		for y := 0; y < 2; y++ {
+			for x := 0; x < 2; x++ {
+				if got := dst.RGBAAt(x, y); got.R != 128 || got.A != 255 {
+					t.Errorf("(%d,%d) = %v, want gray", x, y, got)
+				}
+			}
+		}
+	})
+
+	t.Run("non-integer scale keeps uniform color", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, 7, 5))
+		for i := 0; i < len(src.Pix); i += 4 {
+			src.Pix[i], src.Pix[i+1], src.Pix[i+2], src.Pix[i+3] = 10, 200, 90, 255
+		}
+		dst := z.resize(src, image.Pt(3, 2))
+		for i := 0; i < len(dst.Pix); i += 4 {
+			if got := dst.Pix[i : i+4]; got[0] != 10 || got[1] != 200 || got[2] != 90 || got[3] != 255 {
+				t.Fatalf("pixel %d = %v, want [10 200 90 255]", i/4, got)
+			}
+		}
+	})
+
+	t.Run("transparent pixels do not bleed color", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, 2, 1))
+		src.SetRGBA(0, 0, color.RGBA{200, 0, 0, 200}) // 알파가 곱해진 값
+		src.SetRGBA(1, 0, color.RGBA{0, 0, 0, 0})
+		got := z.resize(src, image.Pt(1, 1)).RGBAAt(0, 0)
+		if got != (color.RGBA{100, 0, 0, 100}) {
+			t.Errorf("got %v, want {100 0 0 100}", got)
+		}
+	})
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		width, height, maxSize int
+		want                   image.Point
+	}{
+		{4000, 3000, 1280, image.Pt(1280, 960)},
+		{3000, 4000, 160, image.Pt(120, 160)},
+		{10000, 10, 16, image.Pt(16, 1)},
+	}
+	for _, tt := range tests {
+		if got := fit(tt.width, tt.height, tt.maxSize); got != tt.want {
+			t.Errorf("fit(%d, %d, %d) = %v, want %v", tt.width, tt.height, tt.maxSize, got, tt.want)
+		}
+	}
+}
+
+var testSpecs = []VariantSpec{
+	{Name: "small", MaxSize: 16},
+	{Name: "medium", MaxSize: 48},
+	{Name: "large", MaxSize: 1280},
+}
+
+func TestProcessJPEGWithGPSExif(t *testing.T) {
+	data := insertJPEGSegments(encodeJPEG(t, gradient(80, 60)), exifSegment(1))
+
+	result, err := Process(data, testSpecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ContentType != "image/jpeg" || result.Width != 80 || result.Height != 60 {
+		t.Errorf("got %s %dx%d, want image/jpeg 80x60", result.ContentType, result.Width, result.Height)
+	}
+	assertNoJPEGMetadata(t, "original", result.Original)
+
+	// 원본보다 큰 썸네일은 만들지 않고, 순서는 지정한 순서를 따름
+	if len(result.Variants) != 2 || result.Variants[0].Name != "small" || result.Variants[1].Name != "medium" {
+		t.Fatalf("unexpected variants %+v", result.Variants)
+	}
+	for _, variant := range result.Variants {
+		assertNoJPEGMetadata(t, variant.Name, variant.Data)
+		img, err := jpeg.Decode(bytes.NewReader(variant.Data))
+		if err != nil {
+			t.Fatalf("%s: %v", variant.Name, err)
+		}
+		if img.Bounds().Dx() != variant.Width || img.Bounds().Dy() != variant.Height {
+			t.Errorf("%s: decoded %v, reported %dx%d", variant.Name, img.Bounds(), variant.Width, variant.Height)
+		}
+	}
+	if w, h := result.Variants[1].Width, result.Variants[1].Height; w != 48 || h != 36 {
+		t.Errorf("medium = %dx%d, want 48x36", w, h)
+	}
+}
+
+func TestProcessAppliesExifOrientation(t *testing.T) {
+	data := insertJPEGSegments(encodeJPEG(t, gradient(80, 40)), exifSegment(6))
+
+	result, err := Process(data, testSpecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Width != 40 || result.Height != 80 {
+		t.Errorf("got %dx%d, want 40x80 after rotation", result.Width, result.Height)
+	}
+	assertNoJPEGMetadata(t, "original", result.Original)
+
+	img, err := jpeg.Decode(bytes.NewReader(result.Original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 80 {
+		t.Errorf("re-encoded original is %v, want 40x80", img.Bounds())
+	}
+	// 시계 방향 90도: 원본 왼쪽 아래(빨강 0, 초록 최대)가 왼쪽 위로 옴
+	r, g, _, _ := img.At(0, 0).RGBA()
+	if r>>8 > 40 || g>>8 < 200 {
+		t.Errorf("top-left after rotation = r%d g%d, want r≈0 g≈255", r>>8, g>>8)
+	}
+}
+
+func TestProcessPlaceholder(t *testing.T) {
+	data := encodePNG(t, gradient(64, 32))
+
+	result, err := Process(data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const prefix = "data:image/jpeg;base64,"
+	if !strings.HasPrefix(result.Placeholder, prefix) {
+		t.Fatalf("placeholder = %.40q", result.Placeholder)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(result.Placeholder, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := jpeg.Decode(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != placeholderSize || img.Bounds().Dy() != placeholderSize/2 {
+		t.Errorf("placeholder is %v, want %dx%d", img.Bounds(), placeholderSize, placeholderSize/2)
+	}
+}
+
+func TestProcessTransparentPNG(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	img.SetNRGBA(5, 5, color.NRGBA{255, 0, 0, 128})
+	data := insertPNGChunk(encodePNG(t, img), "tEXt", []byte("GPS\x0037.5665,126.9780"))
+
+	result, err := Process(data, testSpecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(result.Original, []byte("tEXt")) {
+		t.Error("original still has a tEXt chunk")
+	}
+	if len(result.Variants) != 1 || result.Variants[0].ContentType != "image/png" {
+		t.Fatalf("unexpected variants %+v", result.Variants)
+	}
+	if !strings.HasPrefix(result.Placeholder, "data:image/png;base64,") {
+		t.Errorf("placeholder should be PNG for transparent images")
+	}
+}
+
+func TestProcessRejectsOversizedImage(t *testing.T) {
+	data := encodePNG(t, gradient(2, 2))
+	// IHDR의 크기만 바꾸고 CRC를 다시 계산 (픽셀 데이터는 디코딩하지 않음)
+	binary.BigEndian.PutUint32(data[16:], 6000)
+	binary.BigEndian.PutUint32(data[20:], 5000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, err := Process(data, testSpecs); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("got %v, want ErrImageTooLarge", err)
+	}
+}
+
+func TestProcessRejectsUnknownData(t *testing.T) {
+	if _, err := Process([]byte("plain text"), testSpecs); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("got %v, want ErrUnsupportedFormat", err)
+	}
+}
+
+func TestIsUnsupportedImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		head        string
+		want        bool
+	}{
+		{"jpeg", "image/jpeg", "\xFF\xD8\xFF", false},
+		{"png", "image/png", "\x89PNG", false},
+		{"gif", "image/gif", "GIF89a", false},
+		{"webp", "image/webp", "RIFF\x00\x00\x00\x00WEBPVP8 ", true},
+		{"bmp", "image/bmp", "BM", true},
+		{"heic", "application/octet-stream", "\x00\x00\x00\x18ftypheic\x00\x00\x00\x00", true},
+		{"avif", "application/octet-stream", "\x00\x00\x00\x1cftypavif\x00\x00\x00\x00", true},
+		{"tiff little endian", "application/octet-stream", "II*\x00\x08\x00\x00\x00", true},
+		{"tiff big endian", "application/octet-stream", "MM\x00*\x00\x00\x00\x08", true},
+		{"mp4", "video/mp4", "\x00\x00\x00\x18ftypisom\x00\x00\x00\x00", false},
+		{"pdf", "application/pdf", "%PDF-1.7", false},
+	}
+	for _, tt := range tests {
+		if got := IsUnsupportedImage(tt.contentType, []byte(tt.head)); got != tt.want {
+			t.Errorf("%s: IsUnsupportedImage = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	markerSOI  = 0xD8
+	markerEOI  = 0xD9
+	markerSOS  = 0xDA
+	markerAPP1 = 0xE1 // EXIF, XMP
+	markerAPP2 = 0xE2 // ICC 프로필, MPF
+	markerCOM  = 0xFE
+
+	exifOrientationTag = 0x0112
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// StripJPEGMetadata JPEG에서 EXIF(GPS 포함), XMP, IPTC, 주석 세그먼트와 EOI 뒤의 데이터를 제거
+// 픽셀 데이터는 다시 인코딩하지 않으며, 색 재현에 필요한 ICC 프로필과 Adobe 세그먼트는 유지한다.
+func StripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != markerSOI {
+		return nil, ErrCorruptImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, markerSOI)
+	i := 2
+	for {
+		if i+1 >= len(data) || data[i] != 0xFF {
+			return nil, ErrCorruptImage
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // 채움 바이트
+			i++
+			continue
+		}
+		i += 2
+
+		if marker == markerEOI {
+			return append(out, 0xFF, markerEOI), nil
+		}
+		if (marker >= 0xD0 && marker <= 0xD7) || marker == 0x01 { // 길이가 없는 마커
+			out = append(out, 0xFF, marker)
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, ErrCorruptImage
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, ErrCorruptImage
+		}
+		if keepJPEGSegment(marker, data[i+2:i+length]) {
+			out = append(out, data[i-2:i+length]...)
+		}
+		i += length
+
+		if marker == markerSOS {
+			// 압축 데이터는 다음 마커(RST 제외)까지 그대로 복사
+			start := i
+			for i+1 < len(data) && (data[i] != 0xFF || data[i+1] == 0 || (data[i+1] >= 0xD0 && data[i+1] <= 0xD7)) {
+				i++
+			}
+			if i+1 >= len(data) {
+				// EOI가 없는 파일은 끝까지 복사하고 EOI를 붙임
+				return append(append(out, data[start:]...), 0xFF, markerEOI), nil
+			}
+			out = append(out, data[start:i]...)
+		}
+	}
+}
+
+func keepJPEGSegment(marker byte, payload []byte) bool {
+	switch {
+	case marker == markerAPP1, marker == markerCOM:
+		return false
+	case marker == markerAPP2:
+		return bytes.HasPrefix(payload, []byte("ICC_PROFILE\x00"))
+	case marker >= 0xE3 && marker <= 0xEF:
+		return marker == 0xEE // Adobe (색 변환 정보)
+	}
+	return true
+}
+
+// StripPNGMetadata PNG에서 EXIF, 텍스트, 수정 시각 청크와 IEND 뒤의 데이터를 제거
+func StripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrCorruptImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	i := len(pngSignature)
+	for {
+		if i+8 > len(data) {
+			return nil, ErrCorruptImage
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length // 길이, 종류, 데이터, CRC
+		if end > len(data) {
+			return nil, ErrCorruptImage
+		}
+
+		switch chunkType {
+		case "eXIf", "tEXt", "iTXt", "zTXt", "tIME":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		if chunkType == "IEND" {
+			return out, nil
+		}
+		i = end
+	}
+}
+
+// JPEG의 EXIF 회전 방향 (없거나 읽을 수 없으면 1)
+func jpegOrientation(data []byte) int {
+	i := 2
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		if marker == markerSOS || marker == markerEOI {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		payload := data[i+4 : i+2+length]
+		if marker == markerAPP1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			return exifOrientation(payload[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// TIFF 형식 EXIF의 첫 번째 IFD에서 회전 방향 태그 읽기
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			break
+		}
+	}
+	return 1
+}
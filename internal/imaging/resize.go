@@ -0,0 +1,163 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+	"sync"
+)
+
+// 원본 크기 RGBA 버퍼 재사용 (워커마다 큰 버퍼를 매번 새로 할당하지 않도록 함)
+var pixelPool sync.Pool
+
+func borrowRGBA(width, height int) *image.RGBA {
+	n := width * height * 4
+	pix, _ := pixelPool.Get().(*[]uint8)
+	if pix == nil || cap(*pix) < n {
+		buf := make([]uint8, n)
+		pix = &buf
+	}
+	return &image.RGBA{Pix: (*pix)[:n], Stride: width * 4, Rect: image.Rect(0, 0, width, height)}
+}
+
+func releaseRGBA(img *image.RGBA) {
+	pix := img.Pix[:0]
+	pixelPool.Put(&pix)
+}
+
+// 디코딩된 이미지를 원점 기준 RGBA(알파 곱셈)로 변환하면서 EXIF 회전 방향(2~8)을 적용
+// 회전한 이미지를 따로 만들지 않도록 한 줄씩 변환해 바로 회전된 위치에 쓴다.
+// 다 쓴 결과는 releaseRGBA로 반환한다.
+func toRGBA(src image.Image, orientation int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if orientation < 2 || orientation > 8 {
+		dst := borrowRGBA(width, height)
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+		return dst
+	}
+
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+	dst := borrowRGBA(dstWidth, dstHeight)
+	row := image.NewRGBA(image.Rect(0, 0, width, 1))
+	for sy := 0; sy < height; sy++ {
+		draw.Draw(row, row.Bounds(), src, image.Pt(bounds.Min.X, bounds.Min.Y+sy), draw.Src)
+		for sx := 0; sx < width; sx++ {
+			var x, y int
+			switch orientation {
+			case 2: // 좌우 반전
+				x, y = width-1-sx, sy
+			case 3: // 180도 회전
+				x, y = width-1-sx, height-1-sy
+			case 4: // 상하 반전
+				x, y = sx, height-1-sy
+			case 5: // 대각선 반전
+				x, y = sy, sx
+			case 6: // 시계 방향 90도 회전
+				x, y = height-1-sy, sx
+			case 7: // 반대 대각선 반전
+				x, y = height-1-sy, width-1-sx
+			case 8: // 반시계 방향 90도 회전
+				x, y = sy, width-1-sx
+			}
+			copy(dst.Pix[y*dst.Stride+x*4:y*dst.Stride+x*4+4], row.Pix[sx*4:])
+		}
+	}
+	return dst
+}
+
+// resizer 면적 평균 축소에 쓰는 작업 버퍼 (한 이미지의 썸네일을 만드는 동안 재사용)
+type resizer struct {
+	row []float32 // 가로로 축소한 원본 한 줄
+	acc []float32 // 결과 이미지 누적값
+}
+
+// 면적 평균으로 축소 (원본을 한 줄씩 가로로 줄인 뒤 해당하는 결과 줄에 비율대로 더함)
+// 작업 버퍼가 결과 크기에 비례하므로 원본이 커도 메모리 사용량이 늘지 않는다.
+// 알파가 곱해진 값으로 평균을 내므로 투명한 가장자리에 색이 번지지 않는다.
+func (z *resizer) resize(src *image.RGBA, size image.Point) *image.RGBA {
+	srcWidth, srcHeight := src.Bounds().Dx(), src.Bounds().Dy()
+	columns := boxWeights(srcWidth, size.X)
+
+	// 원본 줄마다 영향을 주는 결과 줄과 비율
+	contributions := make([][]sampleWeight, srcHeight)
+	for y, weights := range boxWeights(srcHeight, size.Y) {
+		for _, w := range weights {
+			contributions[w.index] = append(contributions[w.index], sampleWeight{index: y, weight: w.weight})
+		}
+	}
+
+	z.row = growFloats(z.row, size.X*4)
+	z.acc = growFloats(z.acc, size.X*size.Y*4)
+	clear(z.acc)
+
+	for sy := 0; sy < srcHeight; sy++ {
+		if len(contributions[sy]) == 0 {
+			continue
+		}
+		pixels := src.Pix[sy*src.Stride:]
+		for x, weights := range columns {
+			var r, g, b, a float32
+			for _, w := range weights {
+				p := pixels[w.index*4:]
+				r += float32(p[0]) * w.weight
+				g += float32(p[1]) * w.weight
+				b += float32(p[2]) * w.weight
+				a += float32(p[3]) * w.weight
+			}
+			z.row[x*4], z.row[x*4+1], z.row[x*4+2], z.row[x*4+3] = r, g, b, a
+		}
+		for _, c := range contributions[sy] {
+			out := z.acc[c.index*size.X*4 : (c.index+1)*size.X*4]
+			for i, v := range z.row {
+				out[i] += v * c.weight
+			}
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	for i, v := range z.acc {
+		dst.Pix[i] = clamp(v)
+	}
+	return dst
+}
+
+func growFloats(buf []float32, n int) []float32 {
+	if cap(buf) < n {
+		return make([]float32, n)
+	}
+	return buf[:n]
+}
+
+type sampleWeight struct {
+	index  int
+	weight float32
+}
+
+// 결과 픽셀마다 겹치는 원본 픽셀과 겹치는 비율 (합은 1)
+func boxWeights(srcSize, dstSize int) [][]sampleWeight {
+	scale := float64(srcSize) / float64(dstSize)
+	result := make([][]sampleWeight, dstSize)
+	for i := range result {
+		start, end := float64(i)*scale, float64(i+1)*scale
+		for j := int(start); j < srcSize && float64(j) < end; j++ {
+			overlap := min(end, float64(j+1)) - max(start, float64(j))
+			if overlap > 0 {
+				result[i] = append(result[i], sampleWeight{index: j, weight: float32(overlap / scale)})
+			}
+		}
+	}
+	return result
+}
+
+func clamp(v float32) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
@@ -1,21 +1,20 @@
 package middleware
 
 import (
+	"chat-go-api/internal/services"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type AuthMiddleware struct {
-	secret string
+	authService *services.AuthService
 }
 
 // NewAuthMiddleware 초기화
-func NewAuthMiddleware(secret string) *AuthMiddleware {
-	return &AuthMiddleware{secret: secret}
+func NewAuthMiddleware(authService *services.AuthService) *AuthMiddleware {
+	return &AuthMiddleware{authService: authService}
 }
 
 // MiddlewareFunc 인증 미들웨어 함수
@@ -30,34 +29,21 @@ func (a *AuthMiddleware) MiddlewareFunc(next http.Handler) http.Handler {
 
 		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		// 토큰 검증
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(a.secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
-			return
-		}
-
-		// 사용자 정보 추출
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		// 토큰 및 세션 검증
+		claims, err := a.authService.ValidateAccessToken(tokenString)
+		if errors.Is(err, services.ErrAccountDisabled) {
+			http.Error(w, "Account disabled", http.StatusForbidden)
 			return
 		}
-
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			http.Error(w, "Invalid user_id in token", http.StatusUnauthorized)
+		if err != nil {
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
 			return
 		}
 
 		// 요청 컨텍스트에 사용자 정보 추가
-		ctx := context.WithValue(r.Context(), "user_id", userID)
+		ctx := context.WithValue(r.Context(), "user_id", claims.UserID)
+		ctx = context.WithValue(ctx, "session_id", claims.FamilyID)
+		ctx = context.WithValue(ctx, "role", claims.Role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -0,0 +1,116 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// 이미지 첨부파일 처리 상태 (이미지가 아니면 비어 있음)
+const (
+	AttachmentStatusProcessing = "processing" // 메타데이터 제거와 썸네일 생성 대기 중
+	AttachmentStatusReady      = "ready"
+	AttachmentStatusFailed     = "failed" // 처리할 수 없는 이미지, 원본 다운로드 불가
+)
+
+// Attachment 업로드된 첨부파일
+// 업로드 후 메시지에서 참조되기 전까지는 MessageID가 비어 있다.
+type Attachment struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty"`
+	RoomID      primitive.ObjectID  `bson:"room_id"`
+	UploaderID  primitive.ObjectID  `bson:"uploader_id"`
+	MessageID   *primitive.ObjectID `bson:"message_id,omitempty"`
+	FileName    string              `bson:"file_name"`
+	ContentType string              `bson:"content_type"` // 업로드된 내용으로 판별한 MIME 타입
+	Size        int64               `bson:"size"`
+	SHA256      string              `bson:"sha256"`
+	StorageKey  string              `bson:"storage_key"`
+	CreatedAt   int64               `bson:"created_at"`
+
+	Status      string              `bson:"status,omitempty"`
+	Width       int                 `bson:"width,omitempty"`
+	Height      int                 `bson:"height,omitempty"`
+	Placeholder string              `bson:"placeholder,omitempty"` // 저해상도 블러 미리보기 (data URI)
+	Variants    []AttachmentVariant `bson:"variants,omitempty"`    // 썸네일
+}
+
+// IsDownloadable 원본을 내려받을 수 있는지 확인 (이미지는 메타데이터 제거 후에만 가능)
+func (a *Attachment) IsDownloadable() bool {
+	return a.Status == "" || a.Status == AttachmentStatusReady
+}
+
+// Variant 이름으로 썸네일 찾기
+func (a *Attachment) Variant(name string) *AttachmentVariant {
+	for i := range a.Variants {
+		if a.Variants[i].Name == name {
+			return &a.Variants[i]
+		}
+	}
+	return nil
+}
+
+// AttachmentVariant 이미지 첨부파일의 썸네일
+type AttachmentVariant struct {
+	Name        string `bson:"name"` // small, medium, large
+	Width       int    `bson:"width"`
+	Height      int    `bson:"height"`
+	Size        int64  `bson:"size"`
+	ContentType string `bson:"content_type"`
+	StorageKey  string `bson:"storage_key"`
+}
+
+// MessageAttachment 메시지에 함께 저장되는 첨부파일 정보
+type MessageAttachment struct {
+	ID          primitive.ObjectID `bson:"id"`
+	FileName    string             `bson:"file_name"`
+	ContentType string             `bson:"content_type"`
+	Size        int64              `bson:"size"`
+
+	Status      string              `bson:"status,omitempty"`
+	Width       int                 `bson:"width,omitempty"`
+	Height      int                 `bson:"height,omitempty"`
+	Placeholder string              `bson:"placeholder,omitempty"`
+	Variants    []AttachmentVariant `bson:"variants,omitempty"`
+}
+
+// ToMessageAttachment 메시지에 저장할 첨부파일 정보
+func (a *Attachment) ToMessageAttachment() MessageAttachment {
+	return MessageAttachment{
+		ID:          a.ID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		Status:      a.Status,
+		Width:       a.Width,
+		Height:      a.Height,
+		Placeholder: a.Placeholder,
+		Variants:    a.Variants,
+	}
+}
+
+// AttachmentDTO 첨부파일 정보 (다운로드 URL은 별도로 발급)
+type AttachmentDTO struct {
+	ID          primitive.ObjectID `json:"id"`
+	FileName    string             `json:"file_name"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	SHA256      string             `json:"sha256,omitempty"`
+	CreatedAt   int64              `json:"created_at,omitempty"`
+
+	Status      string                 `json:"status,omitempty"`
+	Width       int                    `json:"width,omitempty"`
+	Height      int                    `json:"height,omitempty"`
+	Placeholder string                 `json:"placeholder,omitempty"`
+	Variants    []AttachmentVariantDTO `json:"variants,omitempty"`
+}
+
+// AttachmentVariantDTO 썸네일 정보 (다운로드 URL 발급 시 variant로 지정)
+type AttachmentVariantDTO struct {
+	Name        string `json:"name"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+}
+
+// AttachmentURLDTO 짧은 시간 동안 유효한 서명된 다운로드 URL
+type AttachmentURLDTO struct {
+	URL       string `json:"url"`
+	ExpiresAt int64  `json:"expires_at"`
+}
@@ -2,9 +2,78 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// 채팅방 종류
+const (
+	RoomTypeGroup  = "group"
+	RoomTypeDirect = "direct" // 1:1 대화방
+)
+
+// 채팅방 공개 범위
+const (
+	RoomVisibilityPrivate = "private" // 초대된 멤버만 (기본값)
+	RoomVisibilityPublic  = "public"  // 누구나 검색하고 참여 가능
+	RoomVisibilityRequest = "request" // 검색 가능, 관리자 승인 후 참여
+)
+
+// 채팅방 내 역할
+const (
+	RoomRoleOwner  = "owner"
+	RoomRoleAdmin  = "admin"
+	RoomRoleMember = "member"
+)
+
 type ChatRoom struct {
-	ID        primitive.ObjectID   `bson:"_id,omitempty"`
-	Name      string               `bson:"name"`
-	Members   []primitive.ObjectID `bson:"members"`
-	CreatedAt int64                `bson:"created_at"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty"`
+	Type        string               `bson:"type"` // 비어 있으면 group
+	Name        string               `bson:"name"`
+	Topic       string               `bson:"topic,omitempty"`
+	Description string               `bson:"description,omitempty"`
+	AvatarURL   string               `bson:"avatar_url,omitempty"`
+	Visibility  string               `bson:"visibility,omitempty"` // 비어 있으면 private
+	DMKey       string               `bson:"dm_key,omitempty"`     // 1:1 대화방 참여자 쌍의 정규화된 키
+	OwnerID     primitive.ObjectID   `bson:"owner_id"`
+	Admins      []primitive.ObjectID `bson:"admins"` // 방장 외 관리 권한을 가진 멤버
+	Members     []primitive.ObjectID `bson:"members"`
+	CreatedAt   int64                `bson:"created_at"`
+	UpdatedAt   int64                `bson:"updated_at,omitempty"`  // 메타데이터 마지막 수정 시각
+	ArchivedAt  int64                `bson:"archived_at,omitempty"` // 0이 아니면 읽기 전용
+
+	// 채팅방 목록 정렬과 미리보기를 위해 메시지 저장 시 함께 갱신
+	LastMessage    *RoomLastMessage `bson:"last_message,omitempty"`
+	LastActivityAt int64            `bson:"last_activity_at"`
+}
+
+// RoomLastMessage 채팅방 목록에 표시할 마지막 메시지 미리보기
+type RoomLastMessage struct {
+	ID         primitive.ObjectID `bson:"id" json:"id"`
+	SenderID   primitive.ObjectID `bson:"sender_id" json:"sender_id"`
+	SenderName string             `bson:"sender_name" json:"sender_name"`
+	Type       string             `bson:"type" json:"type"`
+	Snippet    string             `bson:"snippet" json:"snippet"`
+	CreatedAt  int64              `bson:"created_at" json:"created_at"`
+	Deleted    bool               `bson:"deleted,omitempty" json:"deleted,omitempty"` // 삭제된 메시지면 snippet은 비어 있음
+}
+
+// IsDirect 1:1 대화방 여부
+func (r *ChatRoom) IsDirect() bool {
+	return r.Type == RoomTypeDirect
+}
+
+// IsArchived 보관(읽기 전용) 상태 여부
+func (r *ChatRoom) IsArchived() bool {
+	return r.ArchivedAt != 0
+}
+
+// IsDiscoverable 공개 디렉터리에 노출되는 채팅방인지 여부
+func (r *ChatRoom) IsDiscoverable() bool {
+	return !r.IsArchived() && (r.Visibility == RoomVisibilityPublic || r.Visibility == RoomVisibilityRequest)
+}
+
+// IsValidRoomVisibility 정의된 공개 범위인지 확인
+func IsValidRoomVisibility(visibility string) bool {
+	switch visibility {
+	case RoomVisibilityPrivate, RoomVisibilityPublic, RoomVisibilityRequest:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,40 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type ChatRoomDTO struct {
+	ID          primitive.ObjectID   `json:"id"`
+	Type        string               `json:"type"`
+	Name        string               `json:"name"` // 1:1 대화방은 상대방 이름
+	Topic       string               `json:"topic,omitempty"`
+	Description string               `json:"description,omitempty"`
+	AvatarURL   string               `json:"avatar_url,omitempty"`
+	Visibility  string               `json:"visibility"`
+	OwnerID     *primitive.ObjectID  `json:"owner_id,omitempty"` // 1:1 대화방은 방장이 없어 생략
+	Admins      []primitive.ObjectID `json:"admins"`
+	Members     []primitive.ObjectID `json:"members"`
+	PeerID      *primitive.ObjectID  `json:"peer_id,omitempty"` // 1:1 대화방 상대방
+	CreatedAt   int64                `json:"created_at"`
+	UpdatedAt   int64                `json:"updated_at,omitempty"`
+	ArchivedAt  int64                `json:"archived_at,omitempty"`
+
+	LastMessage    *RoomLastMessage `json:"last_message,omitempty"`
+	LastActivityAt int64            `json:"last_activity_at"`
+
+	// 조회한 유저 기준 안 읽은 메시지 수와 그중 자신이 멘션된 메시지 수
+	UnreadCount  int64 `json:"unread_count"`
+	MentionCount int64 `json:"mention_count"`
+}
+
+// PublicRoomDTO 공개 채팅방 디렉터리 항목
+type PublicRoomDTO struct {
+	ID          primitive.ObjectID `json:"id"`
+	Name        string             `json:"name"`
+	Topic       string             `json:"topic,omitempty"`
+	Description string             `json:"description,omitempty"`
+	AvatarURL   string             `json:"avatar_url,omitempty"`
+	Visibility  string             `json:"visibility"`
+	MemberCount int                `json:"member_count"`
+	IsMember    bool               `json:"is_member"`
+	CreatedAt   int64              `json:"created_at"`
+}
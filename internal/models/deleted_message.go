@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeletedMessage 보관 정책에 따라 남겨두는 삭제된 메시지의 원래 내용 (관리 목적)
+// 보관 기간이 지나면 TTL 인덱스로 자동 삭제된다.
+type DeletedMessage struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	MessageID primitive.ObjectID `bson:"message_id"`
+	RoomID    primitive.ObjectID `bson:"room_id"`
+	SenderID  primitive.ObjectID `bson:"sender_id"`
+	DeletedBy primitive.ObjectID `bson:"deleted_by"`
+	Content   string             `bson:"content"`
+	Edits     []*MessageEdit     `bson:"edits,omitempty"` // 수정 이력 (최신순)
+	CreatedAt int64              `bson:"created_at"`
+	DeletedAt int64              `bson:"deleted_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// 참여 요청 상태
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestDenied   = "denied"
+)
+
+type JoinRequest struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	RoomID    primitive.ObjectID `bson:"room_id"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Status    string             `bson:"status"`
+	DecidedBy primitive.ObjectID `bson:"decided_by,omitempty"`
+	CreatedAt int64              `bson:"created_at"`
+	DecidedAt int64              `bson:"decided_at,omitempty"`
+}
+
+type JoinRequestDTO struct {
+	ID        primitive.ObjectID `json:"id"`
+	RoomID    primitive.ObjectID `json:"room_id"`
+	UserID    primitive.ObjectID `json:"user_id"`
+	UserName  string             `json:"user_name"`
+	Status    string             `json:"status"`
+	CreatedAt int64              `json:"created_at"`
+	DecidedAt int64              `json:"decided_at,omitempty"`
+}
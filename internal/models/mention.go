@@ -0,0 +1,54 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// 멘션 종류
+const (
+	MentionTypeUser = "user"
+	MentionTypeHere = "here" // 메시지를 보낼 때 접속 중인 멤버
+	MentionTypeRoom = "room" // 채팅방 멤버 전체
+)
+
+// MessageMention 메시지 내용의 멘션 (Offset, Length는 내용에서의 문자(rune) 단위 위치)
+type MessageMention struct {
+	Type   string              `bson:"type"`
+	UserID *primitive.ObjectID `bson:"user_id,omitempty"` // type이 user일 때 멘션된 유저
+	Offset int                 `bson:"offset"`
+	Length int                 `bson:"length"`
+}
+
+// Mention 멘션된 유저별 멘션 보관함 항목
+type Mention struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	RoomID    primitive.ObjectID `bson:"room_id"`
+	MessageID primitive.ObjectID `bson:"message_id"`
+	SenderID  primitive.ObjectID `bson:"sender_id"`
+	Type      string             `bson:"type"`       // 유저가 멘션된 방식 (user, here, room)
+	Snippet   string             `bson:"snippet"`    // 메시지 미리보기
+	CreatedAt int64              `bson:"created_at"` // 메시지 작성 시각
+	ReadAt    int64              `bson:"read_at,omitempty"`
+}
+
+// MessageMentionDTO 메시지에 포함된 멘션
+type MessageMentionDTO struct {
+	Type     string              `json:"type"`
+	UserID   *primitive.ObjectID `json:"user_id,omitempty"`
+	UserName string              `json:"user_name,omitempty"`
+	Offset   int                 `json:"offset"`
+	Length   int                 `json:"length"`
+}
+
+// MentionDTO 멘션 보관함 항목
+type MentionDTO struct {
+	ID         primitive.ObjectID `json:"id"`
+	RoomID     primitive.ObjectID `json:"room_id"`
+	RoomName   string             `json:"room_name,omitempty"`
+	MessageID  primitive.ObjectID `json:"message_id"`
+	SenderID   primitive.ObjectID `json:"sender_id"`
+	SenderName string             `json:"sender_name"`
+	Type       string             `json:"type"`
+	Snippet    string             `json:"snippet"`
+	CreatedAt  int64              `json:"created_at"`
+	ReadAt     int64              `json:"read_at,omitempty"`
+}
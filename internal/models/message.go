@@ -2,10 +2,49 @@ package models
 
 import "go.mongodb.org/mongo-driver/bson/primitive"
 
+// 메시지 종류
+const (
+	MessageTypeText   = "text"
+	MessageTypeSystem = "system" // 채팅방 변경 등 서버가 생성한 메시지
+)
+
 type Message struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	RoomID    primitive.ObjectID `bson:"room_id"`
 	SenderID  primitive.ObjectID `bson:"sender_id"`
+	Type      string             `bson:"type,omitempty"` // 비어 있으면 text
 	Content   string             `bson:"content"`
 	CreatedAt int64              `bson:"created_at"`
+	EditedAt  int64              `bson:"edited_at,omitempty"` // 마지막 수정 시각
+
+	// 삭제된 메시지는 내용을 비운 채 타임라인에 남음 (tombstone)
+	DeletedAt int64               `bson:"deleted_at,omitempty"`
+	DeletedBy *primitive.ObjectID `bson:"deleted_by,omitempty"`
+
+	Attachments []MessageAttachment `bson:"attachments,omitempty"`
+
+	// 내용에서 찾은 멘션과 멘션된 유저 ID 목록 (안 읽은 멘션 수 계산에 사용, 작성자 제외)
+	Mentions         []MessageMention     `bson:"mentions,omitempty"`
+	MentionedUserIDs []primitive.ObjectID `bson:"mentioned_user_ids,omitempty"`
+
+	// 리액션별 누른 유저 목록 (예: {"👍": [userID, ...]})
+	Reactions map[string][]primitive.ObjectID `bson:"reactions,omitempty"`
+
+	// 스레드 답글이면 부모 메시지 ID
+	ParentID *primitive.ObjectID `bson:"parent_id,omitempty"`
+
+	// 스레드 부모 메시지의 답글 요약 (답글이 저장될 때 갱신)
+	ReplyCount         int64                `bson:"reply_count,omitempty"`
+	LastReplyAt        int64                `bson:"last_reply_at,omitempty"`
+	ThreadParticipants []primitive.ObjectID `bson:"thread_participants,omitempty"`
+}
+
+// IsReply 스레드 답글 여부
+func (m *Message) IsReply() bool {
+	return m.ParentID != nil
+}
+
+// IsDeleted 삭제된 메시지 여부
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt != 0
 }
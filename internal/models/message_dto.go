@@ -7,6 +7,32 @@ type MessageDTO struct {
 	RoomID     primitive.ObjectID `json:"room_id"`
 	SenderID   primitive.ObjectID `json:"sender_id"`
 	SenderName string             `json:"sender_name"` // 작성자 이름
+	Type       string             `json:"type"`
 	Content    string             `json:"content"`
 	CreatedAt  int64              `json:"created_at"`
+	EditedAt   int64              `json:"edited_at,omitempty"`
+	DeletedAt  int64              `json:"deleted_at,omitempty"` // 0이 아니면 삭제된 메시지 (content는 비어 있음)
+
+	Attachments []AttachmentDTO     `json:"attachments,omitempty"`
+	Reactions   []ReactionDTO       `json:"reactions,omitempty"`
+	Mentions    []MessageMentionDTO `json:"mentions,omitempty"`
+
+	ParentID           *primitive.ObjectID  `json:"parent_id,omitempty"`
+	ReplyCount         int64                `json:"reply_count,omitempty"`
+	LastReplyAt        int64                `json:"last_reply_at,omitempty"`
+	ThreadParticipants []primitive.ObjectID `json:"thread_participants,omitempty"`
+}
+
+// ReactionDTO 메시지의 리액션별 집계
+type ReactionDTO struct {
+	Emoji   string               `json:"emoji"`
+	Count   int                  `json:"count"`
+	UserIDs []primitive.ObjectID `json:"user_ids"`
+}
+
+// MessageSearchHitDTO 메시지 검색 결과 항목
+type MessageSearchHitDTO struct {
+	Message   *MessageDTO `json:"message"`
+	Highlight string      `json:"highlight"` // 검색어를 <mark>로 감싼 내용 (HTML 이스케이프됨)
+	Score     float64     `json:"score"`
 }
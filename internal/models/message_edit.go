@@ -0,0 +1,13 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// MessageEdit 메시지 수정 이력 (수정 전 내용을 보관)
+type MessageEdit struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MessageID primitive.ObjectID `bson:"message_id" json:"message_id"`
+	RoomID    primitive.ObjectID `bson:"room_id" json:"room_id"`
+	EditorID  primitive.ObjectID `bson:"editor_id" json:"editor_id"`
+	Content   string             `bson:"content" json:"content"`     // 수정 전 내용
+	EditedAt  int64              `bson:"edited_at" json:"edited_at"` // 이 내용이 대체된 시각
+}
@@ -0,0 +1,22 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// ReadPosition 유저별 채팅방 읽음 위치 (마지막으로 읽은 메시지)
+type ReadPosition struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty"`
+	RoomID            primitive.ObjectID `bson:"room_id"`
+	UserID            primitive.ObjectID `bson:"user_id"`
+	LastReadMessageID primitive.ObjectID `bson:"last_read_message_id"`
+	LastReadAt        int64              `bson:"last_read_at"` // 마지막으로 읽은 메시지의 작성 시각
+	UpdatedAt         int64              `bson:"updated_at"`
+}
+
+// ReadReceiptDTO 채팅방 멤버의 읽음 위치
+type ReadReceiptDTO struct {
+	UserID            primitive.ObjectID `json:"user_id"`
+	UserName          string             `json:"user_name"`
+	LastReadMessageID primitive.ObjectID `json:"last_read_message_id"`
+	LastReadAt        int64              `json:"last_read_at"`
+	UpdatedAt         int64              `json:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// 사용자 역할
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Permission 역할에 부여되는 권한
+type Permission string
+
+const (
+	PermissionReadUsers   Permission = "users:read"
+	PermissionManageUsers Permission = "users:manage"
+)
+
+// 역할별 권한 목록
+var RolePermissions = map[string][]Permission{
+	RoleUser:  {},
+	RoleAdmin: {PermissionReadUsers, PermissionManageUsers},
+}
+
+// IsValidRole 정의된 역할인지 확인
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// HasPermission 역할이 권한을 가지고 있는지 확인
+func HasPermission(role string, permission Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
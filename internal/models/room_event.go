@@ -0,0 +1,32 @@
+package models
+
+// 채팅방 시스템 이벤트 종류
+const (
+	EventMemberAdded       = "member.added"
+	EventMemberJoined      = "member.joined"
+	EventMemberRemoved     = "member.removed"
+	EventMemberLeft        = "member.left"
+	EventMemberRoleChanged = "member.role_changed"
+	EventOwnerChanged      = "room.owner_changed"
+	EventRoomUpdated       = "room.updated"
+	EventJoinRequested     = "join_request.created"
+	EventRoomArchived      = "room.archived"
+	EventRoomUnarchived    = "room.unarchived"
+	EventRoomClosed        = "room.closed" // 채팅방 삭제, 이후 연결이 종료됨
+	EventThreadUpdated     = "thread.updated"
+	EventMessageUpdated    = "message.updated"
+	EventMessageDeleted    = "message.deleted"
+	EventReactionAdded     = "reaction.added"
+	EventReactionRemoved   = "reaction.removed"
+	EventReadUpdated       = "read.updated"
+	EventMentionCreated    = "mention.created" // 멘션된 유저에게만 전달
+	EventError             = "error"           // 거부된 요청을 보낸 연결에만 전달
+)
+
+// RoomEvent WebSocket으로 전달되는 채팅방 이벤트
+type RoomEvent struct {
+	Type      string      `json:"type"`
+	RoomID    string      `json:"room_id"`
+	Data      interface{} `json:"data,omitempty"`
+	CreatedAt int64       `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type RoomInvite struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	Code      string             `bson:"code"`
+	RoomID    primitive.ObjectID `bson:"room_id"`
+	CreatedBy primitive.ObjectID `bson:"created_by"`
+	Role      string             `bson:"role"`       // 참여 시 부여할 역할 (member/admin)
+	MaxUses   int                `bson:"max_uses"`   // 0이면 무제한
+	Uses      int                `bson:"uses"`       // 사용 횟수
+	ExpiresAt int64              `bson:"expires_at"` // 0이면 만료 없음
+	RevokedAt int64              `bson:"revoked_at"` // 0이면 유효
+	CreatedAt int64              `bson:"created_at"`
+}
+
+type RoomInviteDTO struct {
+	Code      string             `json:"code"`
+	RoomID    primitive.ObjectID `json:"room_id"`
+	CreatedBy primitive.ObjectID `json:"created_by"`
+	Role      string             `json:"role"`
+	MaxUses   int                `json:"max_uses"`
+	Uses      int                `json:"uses"`
+	ExpiresAt int64              `json:"expires_at,omitempty"`
+	RevokedAt int64              `json:"revoked_at,omitempty"`
+	CreatedAt int64              `json:"created_at"`
+}
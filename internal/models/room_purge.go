@@ -0,0 +1,10 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+// RoomPurge 삭제된 채팅방의 데이터 정리 작업 표시
+// 정리가 끝나면 삭제되며, 남아 있으면 서버 시작 시 정리를 다시 시도한다.
+type RoomPurge struct {
+	RoomID      primitive.ObjectID `bson:"_id"`
+	RequestedAt int64              `bson:"requested_at"`
+}
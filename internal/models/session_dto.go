@@ -0,0 +1,11 @@
+package models
+
+type SessionDTO struct {
+	ID         string `json:"id"` // 세션(토큰 패밀리) ID
+	UserAgent  string `json:"user_agent"`
+	IPAddress  string `json:"ip_address"`
+	CreatedAt  int64  `json:"created_at"`
+	LastUsedAt int64  `json:"last_used_at"`
+	RevokedAt  int64  `json:"revoked_at,omitempty"`
+	Current    bool   `json:"current"` // 요청한 세션 여부
+}
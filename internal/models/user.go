@@ -1,6 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type User struct {
 	ID              primitive.ObjectID `bson:"_id,omitempty"`
@@ -9,19 +13,44 @@ type User struct {
 	Name            string             `bson:"name"`
 	Role            string             `bson:"role"` // 예: "user", "admin"
 	IsEmailVerified bool               `bson:"is_email_verified"`
-	CreatedAt       int64              `bson:"created_at"` // UNIX 타임스탬프
-	UpdatedAt       int64              `bson:"updated_at"`
+	IsDisabled      bool               `bson:"is_disabled"` // 관리자에 의해 비활성화된 계정
+	DisabledAt      int64              `bson:"disabled_at,omitempty"`
+
+	// 2단계 인증 (TOTP)
+	TOTPEnabled       bool     `bson:"totp_enabled"`
+	TOTPSecret        string   `bson:"totp_secret,omitempty"`
+	TOTPPendingSecret string   `bson:"totp_pending_secret,omitempty"` // 등록 확인 전 시크릿
+	TOTPLastStep      int64    `bson:"totp_last_step"`                // 마지막으로 사용된 타임 스텝 (재사용 방지)
+	RecoveryCodes     []string `bson:"recovery_codes,omitempty"`      // 복구 코드 해시
+
+	CreatedAt int64 `bson:"created_at"` // UNIX 타임스탬프
+	UpdatedAt int64 `bson:"updated_at"`
 }
 
 type LoginHistory struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty"`
 	UserID       primitive.ObjectID `bson:"user_id"`
+	FamilyID     string             `bson:"family_id"` // 토큰 패밀리 ID (로그인 1회당 하나)
 	AccessToken  string             `bson:"access_token"`
-	RefreshToken string             `bson:"refresh_token"`
-	CreatedAt    int64              `bson:"created_at"` // UNIX 타임스탬프
+	RefreshToken string             `bson:"refresh_token"` // 현재 유효한 refresh 토큰
+	UserAgent    string             `bson:"user_agent"`
+	IPAddress    string             `bson:"ip_address"`
+	RevokedAt    int64              `bson:"revoked_at"`   // 0이면 유효
+	LastUsedAt   int64              `bson:"last_used_at"` // 마지막 토큰 재발급 시각
+	CreatedAt    int64              `bson:"created_at"`   // UNIX 타임스탬프
 	UpdatedAt    int64              `bson:"updated_at"`
 }
 
+// TwoFactorChallenge 2단계 로그인 챌린지 시도 기록 (코드 대입 방지)
+type TwoFactorChallenge struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	ChallengeID string             `bson:"challenge_id"`
+	UserID      primitive.ObjectID `bson:"user_id"`
+	Attempts    int                `bson:"attempts"`              // 코드 검증 시도 횟수
+	ConsumedAt  int64              `bson:"consumed_at,omitempty"` // 로그인에 사용된 시각
+	ExpiresAt   time.Time          `bson:"expires_at"`            // TTL 인덱스로 자동 삭제
+}
+
 type EmailVerification struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty"`
 	UserID    primitive.ObjectID `bson:"user_id"`
@@ -29,3 +58,12 @@ type EmailVerification struct {
 	CreatedAt int64              `bson:"created_at"`
 	ExpiresAt int64              `bson:"expires_at"`
 }
+
+type PasswordReset struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id"`
+	Token     string             `bson:"token"`
+	CreatedAt int64              `bson:"created_at"`
+	ExpiresAt int64              `bson:"expires_at"`
+	UsedAt    int64              `bson:"used_at"` // 0이면 미사용
+}
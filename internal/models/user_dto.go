@@ -0,0 +1,16 @@
+package models
+
+import "go.mongodb.org/mongo-driver/bson/primitive"
+
+type UserDTO struct {
+	ID               primitive.ObjectID `json:"id"`
+	Email            string             `json:"email"`
+	Name             string             `json:"name"`
+	Role             string             `json:"role"`
+	IsEmailVerified  bool               `json:"is_email_verified"`
+	IsDisabled       bool               `json:"is_disabled"`
+	DisabledAt       int64              `json:"disabled_at,omitempty"`
+	TwoFactorEnabled bool               `json:"two_factor_enabled"`
+	CreatedAt        int64              `json:"created_at"`
+	UpdatedAt        int64              `json:"updated_at"`
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"chat-go-api/internal/models"
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AttachmentRepository struct {
+	db *mongo.Database
+}
+
+func NewAttachmentRepository(db *mongo.Database) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// EnsureIndexes 첨부파일 컬렉션 인덱스 생성
+func (r *AttachmentRepository) EnsureIndexes() error {
+	_, err := r.db.Collection("attachments").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "message_id", Value: 1}, {Key: "created_at", Value: 1}}}, // 메시지 조회, 미사용 업로드 정리
+		{Keys: bson.D{{Key: "room_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"status": "processing"}),
+		},
+	})
+	return err
+}
+
+func (r *AttachmentRepository) CreateAttachment(attachment *models.Attachment) error {
+	result, err := r.db.Collection("attachments").InsertOne(context.TODO(), attachment)
+	if err != nil {
+		return err
+	}
+	attachment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *AttachmentRepository) GetAttachmentByID(attachmentID primitive.ObjectID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Collection("attachments").FindOne(context.TODO(), bson.M{"_id": attachmentID}).Decode(&attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ClaimAttachments 업로더가 같은 채팅방에 올린, 아직 사용되지 않은 첨부파일을 메시지에 연결
+// 하나라도 연결할 수 없으면 연결한 것을 되돌리고 false를 반환한다.
+func (r *AttachmentRepository) ClaimAttachments(attachmentIDs []primitive.ObjectID, uploaderID, roomID, messageID primitive.ObjectID) (bool, error) {
+	result, err := r.db.Collection("attachments").UpdateMany(
+		context.TODO(),
+		bson.M{
+			"_id":         bson.M{"$in": attachmentIDs},
+			"uploader_id": uploaderID,
+			"room_id":     roomID,
+			"message_id":  bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"message_id": messageID}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.ModifiedCount == int64(len(attachmentIDs)) {
+		return true, nil
+	}
+	return false, r.ReleaseAttachments(messageID)
+}
+
+// ReleaseAttachments 메시지 저장에 실패한 경우 첨부파일 연결 해제
+func (r *AttachmentRepository) ReleaseAttachments(messageID primitive.ObjectID) error {
+	_, err := r.db.Collection("attachments").UpdateMany(
+		context.TODO(),
+		bson.M{"message_id": messageID},
+		bson.M{"$unset": bson.M{"message_id": ""}},
+	)
+	return err
+}
+
+// UpdateProcessedAttachment 이미지 처리 결과 반영 후 갱신된 첨부파일 반환
+// 처리 중에 첨부파일이 삭제되었으면 mongo.ErrNoDocuments를 반환한다.
+func (r *AttachmentRepository) UpdateProcessedAttachment(attachment *models.Attachment) (*models.Attachment, error) {
+	var updated models.Attachment
+	err := r.db.Collection("attachments").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": attachment.ID},
+		bson.M{"$set": bson.M{
+			"status":      attachment.Status,
+			"size":        attachment.Size,
+			"sha256":      attachment.SHA256,
+			"width":       attachment.Width,
+			"height":      attachment.Height,
+			"placeholder": attachment.Placeholder,
+			"variants":    attachment.Variants,
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// GetProcessingAttachments 이미지 처리가 끝나지 않은 첨부파일 조회 (서버 재시작 후 재처리용)
+func (r *AttachmentRepository) GetProcessingAttachments() ([]*models.Attachment, error) {
+	cursor, err := r.db.Collection("attachments").Find(context.TODO(), bson.M{"status": models.AttachmentStatusProcessing})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	attachments := []*models.Attachment{}
+	if err := cursor.All(context.TODO(), &attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// GetAttachmentsByIDs 여러 첨부파일 조회 (ID 기준 맵)
+func (r *AttachmentRepository) GetAttachmentsByIDs(attachmentIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.Attachment, error) {
+	return r.findMap(bson.M{"_id": bson.M{"$in": attachmentIDs}})
+}
+
+func (r *AttachmentRepository) DeleteAttachment(attachmentID primitive.ObjectID) error {
+	_, err := r.db.Collection("attachments").DeleteOne(context.TODO(), bson.M{"_id": attachmentID})
+	return err
+}
+
+// DeleteAttachmentsByMessageID 메시지의 첨부파일 삭제 후 삭제된 첨부파일 반환 (원본 삭제용)
+func (r *AttachmentRepository) DeleteAttachmentsByMessageID(messageID primitive.ObjectID) ([]*models.Attachment, error) {
+	return r.deleteMany(bson.M{"message_id": messageID})
+}
+
+// DeleteAttachmentsByRoomID 채팅방의 첨부파일 삭제 후 삭제된 첨부파일 반환 (원본 삭제용)
+func (r *AttachmentRepository) DeleteAttachmentsByRoomID(roomID primitive.ObjectID) ([]*models.Attachment, error) {
+	return r.deleteMany(bson.M{"room_id": roomID})
+}
+
+// DeleteUnclaimedAttachments createdBefore 이전에 올라와 메시지에 연결되지 않은 첨부파일을 최대 limit개 삭제 후 반환 (원본 삭제용)
+// 조회와 삭제 사이에 메시지에 연결된 첨부파일은 삭제하지 않는다.
+func (r *AttachmentRepository) DeleteUnclaimedAttachments(createdBefore int64, limit int64) ([]*models.Attachment, error) {
+	cursor, err := r.db.Collection("attachments").Find(
+		context.TODO(),
+		bson.M{"message_id": bson.M{"$exists": false}, "created_at": bson.M{"$lt": createdBefore}},
+		options.Find().SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var candidates []*models.Attachment
+	if err := cursor.All(context.TODO(), &candidates); err != nil {
+		return nil, err
+	}
+
+	attachments := []*models.Attachment{}
+	for _, attachment := range candidates {
+		result, err := r.db.Collection("attachments").DeleteOne(
+			context.TODO(),
+			bson.M{"_id": attachment.ID, "message_id": bson.M{"$exists": false}},
+		)
+		if err != nil {
+			return attachments, err
+		}
+		if result.DeletedCount == 1 {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) deleteMany(filter bson.M) ([]*models.Attachment, error) {
+	cursor, err := r.db.Collection("attachments").Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	attachments := []*models.Attachment{}
+	if err := cursor.All(context.TODO(), &attachments); err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+
+	// 조회한 문서만 삭제하여 그 사이 추가된 첨부파일의 원본이 남지 않도록 함
+	ids := make([]primitive.ObjectID, 0, len(attachments))
+	for _, attachment := range attachments {
+		ids = append(ids, attachment.ID)
+	}
+	if _, err := r.db.Collection("attachments").DeleteMany(context.TODO(), bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) findMap(filter bson.M) (map[primitive.ObjectID]*models.Attachment, error) {
+	cursor, err := r.db.Collection("attachments").Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var attachments []*models.Attachment
+	if err := cursor.All(context.TODO(), &attachments); err != nil {
+		return nil, err
+	}
+
+	result := make(map[primitive.ObjectID]*models.Attachment, len(attachments))
+	for _, attachment := range attachments {
+		result[attachment.ID] = attachment
+	}
+	return result, nil
+}
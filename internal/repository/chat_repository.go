@@ -3,6 +3,8 @@ package repository
 import (
 	"chat-go-api/internal/models"
 	"context"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,6 +20,77 @@ func NewChatRepository(db *mongo.Database) *ChatRepository {
 	return &ChatRepository{db: db}
 }
 
+// EnsureIndexes 채팅방 컬렉션 인덱스 생성
+func (r *ChatRepository) EnsureIndexes() error {
+	_, err := r.db.Collection("chat_rooms").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "members", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			// 같은 참여자 쌍의 1:1 대화방은 하나만 존재
+			Keys:    bson.D{{Key: "dm_key", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"dm_key": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "visibility", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "members", Value: 1}, {Key: "last_activity_at", Value: -1}, {Key: "_id", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	// 활동 시각이 없는 기존 채팅방은 생성 시각으로 채움
+	_, err = r.db.Collection("chat_rooms").UpdateMany(
+		context.TODO(),
+		bson.M{"last_activity_at": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"last_activity_at": "$created_at"}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// 방장 필드가 생기기 전에 만들어진 채팅방은 첫 번째 멤버를 방장으로 지정
+	// (예전 문서에는 만든 사람이 기록되어 있지 않으므로 실제 생성자와 다를 수 있음)
+	// 관리자가 있는 채팅방은 누군가 이미 권한을 정리한 것이므로 건드리지 않는다.
+	_, err = r.db.Collection("chat_rooms").UpdateMany(
+		context.TODO(),
+		bson.M{
+			"type":      bson.M{"$ne": models.RoomTypeDirect},
+			"members.0": bson.M{"$exists": true},
+			"owner_id":  bson.M{"$in": bson.A{nil, primitive.NilObjectID}},
+			"admins.0":  bson.M{"$exists": false},
+		},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"owner_id": bson.M{"$arrayElemAt": bson.A{"$members", 0}},
+			"admins":   bson.M{"$ifNull": bson.A{"$admins", bson.A{}}},
+		}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	// 채팅방별 유저의 대기 중인 참여 요청은 하나만 존재
+	_, err = r.db.Collection("room_join_requests").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "user_id", Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{"status": models.JoinRequestPending}),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("room_invites").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("read_positions").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+	return err
+}
+
 func (r *ChatRepository) CreateChatRoom(room *models.ChatRoom) error {
 	result, err := r.db.Collection("chat_rooms").InsertOne(context.TODO(), room)
 	if err != nil {
@@ -28,23 +101,525 @@ func (r *ChatRepository) CreateChatRoom(room *models.ChatRoom) error {
 	return err
 }
 
+// GetChatRoomByID 채팅방 단건 조회
+func (r *ChatRepository) GetChatRoomByID(roomID primitive.ObjectID) (*models.ChatRoom, error) {
+	var room models.ChatRoom
+	err := r.db.Collection("chat_rooms").FindOne(context.TODO(), bson.M{"_id": roomID}).Decode(&room)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// GetOrCreateDirectRoom dm_key 기준으로 1:1 대화방을 조회하고 없으면 생성
+func (r *ChatRepository) GetOrCreateDirectRoom(room *models.ChatRoom) (*models.ChatRoom, error) {
+	var result models.ChatRoom
+	upsert := func() error {
+		return r.db.Collection("chat_rooms").FindOneAndUpdate(
+			context.TODO(),
+			bson.M{"dm_key": room.DMKey},
+			bson.M{"$setOnInsert": room},
+			options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+		).Decode(&result)
+	}
+
+	err := upsert()
+	// 동시에 생성 요청이 들어온 경우 유니크 인덱스 충돌이 발생하므로 한 번 더 조회
+	if mongo.IsDuplicateKeyError(err) {
+		err = upsert()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// 채팅방 목록의 보관 상태 필터
+const (
+	ArchivedExclude = "exclude" // 보관된 채팅방 제외 (기본값)
+	ArchivedInclude = "include" // 모두 포함
+	ArchivedOnly    = "only"    // 보관된 채팅방만
+)
+
+// RoomListOptions 유저 채팅방 목록 조회 조건
+// 최근 활동순으로 정렬하며, Before가 지정되면 해당 위치 이후(더 오래된) 채팅방부터 조회한다.
+type RoomListOptions struct {
+	Type             string
+	Archived         string
+	BeforeActivityAt int64
+	BeforeID         primitive.ObjectID
+	Limit            int64
+}
+
 // GetChatRoomsByUserID 유저가 참여한 채팅방 목록 조회
-func (r *ChatRepository) GetChatRoomsByUserID(userID primitive.ObjectID) ([]models.ChatRoom, error) {
-	var chatRooms []models.ChatRoom
+func (r *ChatRepository) GetChatRoomsByUserID(userID primitive.ObjectID, opts RoomListOptions) ([]models.ChatRoom, error) {
+	chatRooms := []models.ChatRoom{}
+
+	filter := bson.M{"members": userID} // members 배열에 userID가 포함된 채팅방 검색
+	switch opts.Type {
+	case "":
+	case models.RoomTypeGroup:
+		// type이 비어 있는 기존 채팅방도 그룹 채팅방
+		filter["type"] = bson.M{"$in": bson.A{nil, "", models.RoomTypeGroup}}
+	default:
+		filter["type"] = opts.Type
+	}
+	switch opts.Archived {
+	case ArchivedInclude:
+	case ArchivedOnly:
+		filter["archived_at"] = bson.M{"$gt": 0}
+	default:
+		filter["archived_at"] = bson.M{"$in": bson.A{nil, 0}} // 보관된 채팅방 제외
+	}
+	if !opts.BeforeID.IsZero() {
+		filter["$or"] = bson.A{
+			bson.M{"last_activity_at": bson.M{"$lt": opts.BeforeActivityAt}},
+			bson.M{"last_activity_at": opts.BeforeActivityAt, "_id": bson.M{"$lt": opts.BeforeID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "last_activity_at", Value: -1}, {Key: "_id", Value: -1}}) // 최근 활동순 정렬
+	if opts.Limit > 0 {
+		findOptions.SetLimit(opts.Limit)
+	}
+
+	cursor, err := r.db.Collection("chat_rooms").Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	if err := cursor.All(context.TODO(), &chatRooms); err != nil {
+		return nil, err
+	}
+
+	return chatRooms, nil
+}
+
+// UpdateLastMessage 채팅방의 마지막 메시지와 활동 시각 갱신
+// 더 늦게 저장된 메시지가 이미 반영되어 있으면 덮어쓰지 않는다.
+func (r *ChatRepository) UpdateLastMessage(roomID primitive.ObjectID, lastMessage *models.RoomLastMessage) error {
+	_, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "last_activity_at": bson.M{"$lte": lastMessage.CreatedAt}},
+		bson.M{"$set": bson.M{"last_message": lastMessage, "last_activity_at": lastMessage.CreatedAt}},
+	)
+	return err
+}
+
+// UpdateLastMessageSnippet 마지막 메시지가 수정된 경우 미리보기 내용 갱신
+func (r *ChatRepository) UpdateLastMessageSnippet(roomID, messageID primitive.ObjectID, snippet string) error {
+	_, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "last_message.id": messageID},
+		bson.M{"$set": bson.M{"last_message.snippet": snippet}},
+	)
+	return err
+}
+
+// MarkLastMessageDeleted 마지막 메시지가 삭제된 경우 미리보기를 비움
+func (r *ChatRepository) MarkLastMessageDeleted(roomID, messageID primitive.ObjectID) error {
+	_, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "last_message.id": messageID},
+		bson.M{"$set": bson.M{"last_message.snippet": "", "last_message.deleted": true}},
+	)
+	return err
+}
+
+// AddMembers 채팅방에 멤버 추가 (이미 멤버인 경우 무시)
+func (r *ChatRepository) AddMembers(roomID primitive.ObjectID, userIDs []primitive.ObjectID) error {
+	_, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID},
+		bson.M{"$addToSet": bson.M{"members": bson.M{"$each": userIDs}}},
+	)
+	return err
+}
+
+// RemoveMember 채팅방에서 멤버 제거 (관리자 목록에서도 제거)
+func (r *ChatRepository) RemoveMember(roomID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "members": userID},
+		bson.M{"$pull": bson.M{"members": userID, "admins": userID}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// SetAdmin 멤버의 관리자 권한 부여/해제
+func (r *ChatRepository) SetAdmin(roomID, userID primitive.ObjectID, admin bool) (bool, error) {
+	update := bson.M{"$pull": bson.M{"admins": userID}}
+	if admin {
+		update = bson.M{"$addToSet": bson.M{"admins": userID}}
+	}
+
+	result, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "members": userID},
+		update,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// TransferOwnership 방장 변경
+// 현재 방장이 여전히 currentOwnerID이고 newOwnerID가 멤버인 경우에만 변경한다.
+func (r *ChatRepository) TransferOwnership(roomID, currentOwnerID, newOwnerID primitive.ObjectID, admins []primitive.ObjectID) (bool, error) {
+	result, err := r.db.Collection("chat_rooms").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": roomID, "owner_id": currentOwnerID, "members": newOwnerID},
+		bson.M{"$set": bson.M{"owner_id": newOwnerID, "admins": admins}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// UpdateChatRoom 채팅방 필드 수정 후 수정된 채팅방 반환
+func (r *ChatRepository) UpdateChatRoom(roomID primitive.ObjectID, fields bson.M) (*models.ChatRoom, error) {
+	var room models.ChatRoom
+	err := r.db.Collection("chat_rooms").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": roomID},
+		bson.M{"$set": fields},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&room)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// SearchDiscoverableRooms 공개 디렉터리 채팅방 검색 (이름/주제)
+func (r *ChatRepository) SearchDiscoverableRooms(query string, limit, page int64) ([]models.ChatRoom, int64, error) {
+	// ChatRoom.IsDiscoverable과 같은 조건 (보관된 채팅방 제외)
+	filter := bson.M{
+		"visibility":  bson.M{"$in": bson.A{models.RoomVisibilityPublic, models.RoomVisibilityRequest}},
+		"archived_at": bson.M{"$in": bson.A{nil, 0}},
+	}
+	if query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"name": pattern},
+			bson.M{"topic": pattern},
+		}
+	}
+
+	total, err := r.db.Collection("chat_rooms").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
 
 	cursor, err := r.db.Collection("chat_rooms").Find(
 		context.TODO(),
-		bson.M{"members": userID}, // members 배열에 userID가 포함된 채팅방 검색
-		options.Find().SetSort(bson.M{"created_at": -1}), // 최신순 정렬
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip((page-1)*limit).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.TODO())
+
+	rooms := []models.ChatRoom{}
+	if err := cursor.All(context.TODO(), &rooms); err != nil {
+		return nil, 0, err
+	}
+	return rooms, total, nil
+}
+
+// CreateJoinRequest 참여 요청 생성 (이미 대기 중인 요청이 있으면 기존 요청 반환)
+func (r *ChatRepository) CreateJoinRequest(request *models.JoinRequest) (*models.JoinRequest, error) {
+	var result models.JoinRequest
+	err := r.db.Collection("room_join_requests").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"room_id": request.RoomID, "user_id": request.UserID, "status": models.JoinRequestPending},
+		bson.M{"$setOnInsert": request},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&result)
+	if mongo.IsDuplicateKeyError(err) {
+		// 동시에 들어온 요청이 먼저 생성한 경우 그 요청을 반환
+		err = r.db.Collection("room_join_requests").FindOne(
+			context.TODO(),
+			bson.M{"room_id": request.RoomID, "user_id": request.UserID, "status": models.JoinRequestPending},
+		).Decode(&result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DenyPendingJoinRequests 채팅방의 대기 중인 참여 요청을 모두 거절 처리
+func (r *ChatRepository) DenyPendingJoinRequests(roomID, decidedBy primitive.ObjectID) error {
+	_, err := r.db.Collection("room_join_requests").UpdateMany(
+		context.TODO(),
+		bson.M{"room_id": roomID, "status": models.JoinRequestPending},
+		bson.M{"$set": bson.M{"status": models.JoinRequestDenied, "decided_by": decidedBy, "decided_at": time.Now().Unix()}},
+	)
+	return err
+}
+
+// GetPendingJoinRequests 채팅방의 대기 중인 참여 요청 목록
+func (r *ChatRepository) GetPendingJoinRequests(roomID primitive.ObjectID) ([]models.JoinRequest, error) {
+	cursor, err := r.db.Collection("room_join_requests").Find(
+		context.TODO(),
+		bson.M{"room_id": roomID, "status": models.JoinRequestPending},
+		options.Find().SetSort(bson.M{"created_at": 1}),
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(context.TODO())
 
-	if err := cursor.All(context.TODO(), &chatRooms); err != nil {
+	requests := []models.JoinRequest{}
+	if err := cursor.All(context.TODO(), &requests); err != nil {
 		return nil, err
 	}
+	return requests, nil
+}
 
-	return chatRooms, nil
+// DecideJoinRequest 대기 중인 참여 요청을 승인/거절 처리
+func (r *ChatRepository) DecideJoinRequest(requestID, roomID primitive.ObjectID, status string, decidedBy primitive.ObjectID) (*models.JoinRequest, error) {
+	var request models.JoinRequest
+	err := r.db.Collection("room_join_requests").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": requestID, "room_id": roomID, "status": models.JoinRequestPending},
+		bson.M{"$set": bson.M{"status": status, "decided_by": decidedBy, "decided_at": time.Now().Unix()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// CreateInvite 초대 코드 생성
+func (r *ChatRepository) CreateInvite(invite *models.RoomInvite) error {
+	result, err := r.db.Collection("room_invites").InsertOne(context.TODO(), invite)
+	if err != nil {
+		return err
+	}
+
+	invite.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetInviteByCode 초대 코드 조회
+func (r *ChatRepository) GetInviteByCode(code string) (*models.RoomInvite, error) {
+	var invite models.RoomInvite
+	err := r.db.Collection("room_invites").FindOne(context.TODO(), bson.M{"code": code}).Decode(&invite)
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// GetInvitesByRoomID 채팅방의 초대 코드 목록 (폐기되지 않은 것만)
+func (r *ChatRepository) GetInvitesByRoomID(roomID primitive.ObjectID) ([]models.RoomInvite, error) {
+	cursor, err := r.db.Collection("room_invites").Find(
+		context.TODO(),
+		bson.M{"room_id": roomID, "revoked_at": 0},
+		options.Find().SetSort(bson.M{"created_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	invites := []models.RoomInvite{}
+	if err := cursor.All(context.TODO(), &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// RevokeInvite 초대 코드 폐기
+func (r *ChatRepository) RevokeInvite(roomID primitive.ObjectID, code string) (bool, error) {
+	result, err := r.db.Collection("room_invites").UpdateOne(
+		context.TODO(),
+		bson.M{"room_id": roomID, "code": code, "revoked_at": 0},
+		bson.M{"$set": bson.M{"revoked_at": time.Now().Unix()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RedeemInvite 초대 코드 사용 처리
+// 폐기/만료되지 않았고 사용 횟수가 남은 경우에만 원자적으로 사용 횟수를 증가시킨다.
+func (r *ChatRepository) RedeemInvite(code string) (*models.RoomInvite, error) {
+	now := time.Now().Unix()
+	var invite models.RoomInvite
+	err := r.db.Collection("room_invites").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{
+			"code":       code,
+			"revoked_at": 0,
+			"$and": bson.A{
+				bson.M{"$or": bson.A{bson.M{"expires_at": 0}, bson.M{"expires_at": bson.M{"$gt": now}}}},
+				bson.M{"$or": bson.A{bson.M{"max_uses": 0}, bson.M{"$expr": bson.M{"$lt": bson.A{"$uses", "$max_uses"}}}}},
+			},
+		},
+		bson.M{"$inc": bson.M{"uses": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&invite)
+	if err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// SetArchived 채팅방 보관/보관 해제 (archivedAt이 0이면 해제)
+func (r *ChatRepository) SetArchived(roomID primitive.ObjectID, archivedAt int64) (*models.ChatRoom, error) {
+	update := bson.M{"$set": bson.M{"archived_at": archivedAt}}
+	if archivedAt == 0 {
+		update = bson.M{"$unset": bson.M{"archived_at": ""}}
+	}
+
+	var room models.ChatRoom
+	err := r.db.Collection("chat_rooms").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": roomID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&room)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// DeleteChatRoom 채팅방 삭제
+func (r *ChatRepository) DeleteChatRoom(roomID primitive.ObjectID) (bool, error) {
+	result, err := r.db.Collection("chat_rooms").DeleteOne(context.TODO(), bson.M{"_id": roomID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
+}
+
+// AddRoomPurge 채팅방 데이터 정리 작업 기록 (이미 있으면 그대로 둠)
+func (r *ChatRepository) AddRoomPurge(purge *models.RoomPurge) error {
+	_, err := r.db.Collection("room_purges").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": purge.RoomID},
+		bson.M{"$setOnInsert": purge},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// GetRoomPurges 아직 끝나지 않은 채팅방 데이터 정리 작업 목록
+func (r *ChatRepository) GetRoomPurges() ([]models.RoomPurge, error) {
+	cursor, err := r.db.Collection("room_purges").Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	purges := []models.RoomPurge{}
+	if err := cursor.All(context.TODO(), &purges); err != nil {
+		return nil, err
+	}
+	return purges, nil
+}
+
+// DeleteRoomPurge 끝난 채팅방 데이터 정리 작업 기록 삭제
+func (r *ChatRepository) DeleteRoomPurge(roomID primitive.ObjectID) error {
+	_, err := r.db.Collection("room_purges").DeleteOne(context.TODO(), bson.M{"_id": roomID})
+	return err
+}
+
+// DeleteRoomRelatedData 삭제된 채팅방의 참여 요청, 초대 코드, 읽음 위치, 멘션, 수정 이력, 보관된 삭제 메시지 삭제
+func (r *ChatRepository) DeleteRoomRelatedData(roomID primitive.ObjectID) error {
+	for _, collection := range []string{"room_join_requests", "room_invites", "read_positions", "mentions", "message_edits", "deleted_messages"} {
+		if _, err := r.db.Collection(collection).DeleteMany(context.TODO(), bson.M{"room_id": roomID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AdvanceReadPosition 읽음 위치를 앞으로만 이동 (이미 더 뒤의 메시지까지 읽었으면 변경하지 않음)
+// 위치가 바뀌었으면 true를 반환한다.
+func (r *ChatRepository) AdvanceReadPosition(position *models.ReadPosition) (bool, error) {
+	_, err := r.db.Collection("read_positions").UpdateOne(
+		context.TODO(),
+		bson.M{
+			"room_id": position.RoomID,
+			"user_id": position.UserID,
+			"$or": bson.A{
+				bson.M{"last_read_at": bson.M{"$lt": position.LastReadAt}},
+				bson.M{"last_read_at": position.LastReadAt, "last_read_message_id": bson.M{"$lt": position.LastReadMessageID}},
+			},
+		},
+		bson.M{"$set": bson.M{
+			"last_read_message_id": position.LastReadMessageID,
+			"last_read_at":         position.LastReadAt,
+			"updated_at":           position.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	// 이미 더 뒤의 위치가 저장되어 있으면 upsert가 유니크 인덱스와 충돌함
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetReadPositionsByUser 유저의 여러 채팅방 읽음 위치 조회 (채팅방 ID 기준 맵)
+func (r *ChatRepository) GetReadPositionsByUser(userID primitive.ObjectID, roomIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.ReadPosition, error) {
+	cursor, err := r.db.Collection("read_positions").Find(
+		context.TODO(),
+		bson.M{"user_id": userID, "room_id": bson.M{"$in": roomIDs}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var positions []*models.ReadPosition
+	if err := cursor.All(context.TODO(), &positions); err != nil {
+		return nil, err
+	}
+
+	result := make(map[primitive.ObjectID]*models.ReadPosition, len(positions))
+	for _, position := range positions {
+		result[position.RoomID] = position
+	}
+	return result, nil
+}
+
+// GetReadPositionsByRoom 채팅방 멤버들의 읽음 위치 조회
+func (r *ChatRepository) GetReadPositionsByRoom(roomID primitive.ObjectID) ([]*models.ReadPosition, error) {
+	cursor, err := r.db.Collection("read_positions").Find(
+		context.TODO(),
+		bson.M{"room_id": roomID},
+		options.Find().SetSort(bson.D{{Key: "last_read_at", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	positions := []*models.ReadPosition{}
+	if err := cursor.All(context.TODO(), &positions); err != nil {
+		return nil, err
+	}
+	return positions, nil
 }
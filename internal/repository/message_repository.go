@@ -3,6 +3,10 @@ package repository
 import (
 	"chat-go-api/internal/models"
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -18,6 +22,52 @@ func NewMessageRepository(db *mongo.Database) *MessageRepository {
 	return &MessageRepository{db: db}
 }
 
+// EnsureIndexes 메시지 컬렉션 인덱스 생성
+func (r *MessageRepository) EnsureIndexes() error {
+	_, err := r.db.Collection("messages").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "room_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			// 안 읽은 멘션 수 계산
+			Keys:    bson.D{{Key: "room_id", Value: 1}, {Key: "mentioned_user_ids", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"mentioned_user_ids": bson.M{"$exists": true}}),
+		},
+		{
+			// 스레드 답글 조회
+			Keys:    bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetPartialFilterExpression(bson.M{"parent_id": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("message_edits").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{{Key: "message_id", Value: 1}, {Key: "edited_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("deleted_messages").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "message_id", Value: 1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("mentions").Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{
+			// 메시지당 유저별 멘션은 하나만 저장
+			Keys:    bson.D{{Key: "message_id", Value: 1}, {Key: "user_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "room_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	return err
+}
+
 func (r *MessageRepository) SaveMessage(msg *models.Message) error {
 	result, err := r.db.Collection("messages").InsertOne(context.TODO(), msg)
 
@@ -62,50 +112,738 @@ func (r *MessageRepository) GetUserByID(userID primitive.ObjectID) (*models.User
 }
 
 // GetTotalMessagesCount 특정 채팅방의 총 메시지 개수 반환
-func (r *MessageRepository) GetTotalMessagesCount(roomID primitive.ObjectID) (int64, error) {
-	total, err := r.db.Collection("messages").CountDocuments(
+// 삭제된 메시지는 페이지에 tombstone으로 포함되므로 개수에도 포함하여 페이지 수가 맞도록 한다.
+func (r *MessageRepository) GetTotalMessagesCount(roomID primitive.ObjectID, hideReplies bool) (int64, error) {
+	filter := roomMessagesFilter(roomID, hideReplies) // 특정 room_id의 메시지 개수 계산
+
+	total, err := r.db.Collection("messages").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// roomMessagesFilter 채팅방 타임라인 조회 조건 (hideReplies면 스레드 답글 제외)
+// 개수 계산과 페이지 조회가 같은 조건을 써야 마지막 페이지가 어긋나지 않는다.
+func roomMessagesFilter(roomID primitive.ObjectID, hideReplies bool) bson.M {
+	filter := bson.M{"room_id": roomID}
+	if hideReplies {
+		filter["parent_id"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+func (r *MessageRepository) GetMessagesByRoomIDWithPagination(roomID primitive.ObjectID, limit int64, page int64, hideReplies bool) ([]*models.Message, error) {
+	cursor, err := r.db.Collection("messages").Find(
 		context.TODO(),
-		bson.M{"room_id": roomID}, // 특정 room_id의 메시지 개수 계산
+		roomMessagesFilter(roomID, hideReplies),
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip((page-1)*limit).
+			SetLimit(limit),
 	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	messages := []*models.Message{}
+	if err := cursor.All(context.TODO(), &messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// MessageAnchor 커서 페이지네이션의 기준 위치 (작성 시각과 메시지 ID)
+type MessageAnchor struct {
+	CreatedAt int64
+	ID        primitive.ObjectID
+}
+
+// GetMessagesBefore 기준 위치보다 이전 메시지를 최신순으로 조회 (anchor가 nil이면 가장 최근부터)
+func (r *MessageRepository) GetMessagesBefore(roomID primitive.ObjectID, anchor *MessageAnchor, limit int64, hideReplies bool) ([]*models.Message, error) {
+	filter := roomMessagesFilter(roomID, hideReplies)
+	if anchor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": anchor.CreatedAt}},
+			bson.M{"created_at": anchor.CreatedAt, "_id": bson.M{"$lt": anchor.ID}},
+		}
+	}
+	return r.findMessages(filter, -1, limit)
+}
+
+// GetMessagesAfter 기준 위치 이후 메시지를 오래된 순으로 조회 (inclusive면 기준 메시지 포함)
+func (r *MessageRepository) GetMessagesAfter(roomID primitive.ObjectID, anchor MessageAnchor, inclusive bool, limit int64, hideReplies bool) ([]*models.Message, error) {
+	idOperator := "$gt"
+	if inclusive {
+		idOperator = "$gte"
+	}
+
+	filter := roomMessagesFilter(roomID, hideReplies)
+	filter["$or"] = bson.A{
+		bson.M{"created_at": bson.M{"$gt": anchor.CreatedAt}},
+		bson.M{"created_at": anchor.CreatedAt, "_id": bson.M{idOperator: anchor.ID}},
+	}
+	return r.findMessages(filter, 1, limit)
+}
+
+// 작성 시각, ID 순으로 정렬하여 메시지 조회 (order: 1 오래된 순, -1 최신순)
+func (r *MessageRepository) findMessages(filter bson.M, order int, limit int64) ([]*models.Message, error) {
+	cursor, err := r.db.Collection("messages").Find(
+		context.TODO(),
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: order}, {Key: "_id", Value: order}}).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	messages := []*models.Message{}
+	if err := cursor.All(context.TODO(), &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// DeleteMessagesByRoomID 특정 채팅방의 모든 메시지 삭제
+func (r *MessageRepository) DeleteMessagesByRoomID(roomID primitive.ObjectID) (int64, error) {
+	result, err := r.db.Collection("messages").DeleteMany(context.TODO(), bson.M{"room_id": roomID})
 	if err != nil {
 		return 0, err
 	}
-	return total, nil
+	return result.DeletedCount, nil
 }
 
-func (r *MessageRepository) GetMessagesByRoomIDWithPagination(roomID primitive.ObjectID, limit int64, page int64) ([]*models.Message, error) {
-	skip := (page - 1) * limit
+// GetMessageByID 메시지 단건 조회
+func (r *MessageRepository) GetMessageByID(messageID primitive.ObjectID) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Collection("messages").FindOne(context.TODO(), bson.M{"_id": messageID}).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
 
-	// 전체 메시지 수 확인
-	totalMessages, err := r.db.Collection("messages").CountDocuments(context.TODO(), bson.M{"room_id": roomID})
+// AddThreadReply 부모 메시지의 답글 수, 마지막 답글 시각, 참여자 갱신 후 갱신된 부모 반환
+func (r *MessageRepository) AddThreadReply(parentID, senderID primitive.ObjectID, repliedAt int64) (*models.Message, error) {
+	var parent models.Message
+	err := r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": parentID},
+		bson.M{
+			"$inc":      bson.M{"reply_count": 1},
+			"$max":      bson.M{"last_reply_at": repliedAt},
+			"$addToSet": bson.M{"thread_participants": senderID},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&parent)
 	if err != nil {
 		return nil, err
 	}
+	return &parent, nil
+}
 
-	// 페이지 초과 확인
-	if skip >= totalMessages {
-		return []*models.Message{}, nil // 빈 배열 반환
+// RemoveThreadReply 답글 삭제 후 부모 메시지의 답글 수와 마지막 답글 시각 갱신 후 갱신된 부모 반환
+// 그 사이 더 최근 답글이 달렸으면 마지막 답글 시각은 그대로 둔다.
+func (r *MessageRepository) RemoveThreadReply(parentID primitive.ObjectID, replyCreatedAt int64) (*models.Message, error) {
+	var lastReplyAt int64
+	var last models.Message
+	err := r.db.Collection("messages").FindOne(
+		context.TODO(),
+		bson.M{"parent_id": parentID, "deleted_at": bson.M{"$exists": false}},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	).Decode(&last)
+	switch {
+	case err == nil:
+		lastReplyAt = last.CreatedAt
+	case !errors.Is(err, mongo.ErrNoDocuments):
+		return nil, err
 	}
 
-	queryOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetSkip(skip)
-	if totalMessages > limit {
-		queryOptions.SetLimit(limit)
+	var parent models.Message
+	err = r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": parentID},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"reply_count": bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$reply_count", 1}}}},
+			"last_reply_at": bson.M{"$cond": bson.A{
+				bson.M{"$gt": bson.A{"$last_reply_at", replyCreatedAt}},
+				"$last_reply_at",
+				lastReplyAt,
+			}},
+		}}}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&parent)
+	if err != nil {
+		return nil, err
 	}
+	return &parent, nil
+}
 
+// GetThreadReplies 삭제되지 않은 스레드 답글을 오래된 순으로 조회
+// 부모 메시지의 답글 수와 맞도록 삭제된 답글은 제외한다.
+func (r *MessageRepository) GetThreadReplies(parentID primitive.ObjectID, limit, page int64) ([]*models.Message, error) {
 	cursor, err := r.db.Collection("messages").Find(
 		context.TODO(),
-		bson.M{"room_id": roomID},
-		queryOptions,
+		bson.M{"parent_id": parentID, "deleted_at": bson.M{"$exists": false}},
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+			SetSkip((page-1)*limit).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	messages := []*models.Message{}
+	if err := cursor.All(context.TODO(), &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// 동시 수정으로 조건부 갱신이 실패했을 때 다시 시도하는 최대 횟수
+const maxEditAttempts = 5
+
+// EditMessage 메시지 내용과 멘션 수정 후 수정 전 내용을 이력으로 저장
+// 이력을 먼저 저장한 뒤 읽은 내용 그대로일 때만 메시지를 바꾸므로,
+// 도중에 실패하거나 동시에 수정해도 수정 전 내용이 사라지지 않는다.
+func (r *MessageRepository) EditMessage(messageID, editorID primitive.ObjectID, content string, mentions []models.MessageMention, mentionedUserIDs []primitive.ObjectID, editedAt int64) (*models.Message, error) {
+	set := bson.M{"content": content, "edited_at": editedAt}
+	unset := bson.M{}
+	if len(mentions) > 0 {
+		set["mentions"] = mentions
+	} else {
+		unset["mentions"] = ""
+	}
+	if len(mentionedUserIDs) > 0 {
+		set["mentioned_user_ids"] = mentionedUserIDs
+	} else {
+		unset["mentioned_user_ids"] = ""
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	for attempt := 0; attempt < maxEditAttempts; attempt++ {
+		var previous models.Message
+		err := r.db.Collection("messages").FindOne(
+			context.TODO(),
+			bson.M{"_id": messageID, "sender_id": editorID, "deleted_at": bson.M{"$exists": false}},
+		).Decode(&previous)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := r.db.Collection("message_edits").InsertOne(context.TODO(), &models.MessageEdit{
+			MessageID: previous.ID,
+			RoomID:    previous.RoomID,
+			EditorID:  editorID,
+			Content:   previous.Content,
+			EditedAt:  editedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		editID := result.InsertedID.(primitive.ObjectID)
+
+		// 읽은 뒤 다른 수정이나 삭제가 없었을 때만 갱신
+		filter := bson.M{"_id": messageID, "content": previous.Content, "deleted_at": bson.M{"$exists": false}}
+		if previous.EditedAt != 0 {
+			filter["edited_at"] = previous.EditedAt
+		} else {
+			filter["edited_at"] = bson.M{"$exists": false}
+		}
+		updateResult, err := r.db.Collection("messages").UpdateOne(context.TODO(), filter, update)
+		if err == nil && updateResult.MatchedCount == 1 {
+			updated := previous
+			updated.Content = content
+			updated.EditedAt = editedAt
+			updated.Mentions = mentions
+			updated.MentionedUserIDs = mentionedUserIDs
+			return &updated, nil
+		}
+
+		// 반영되지 않은 수정의 이력은 지움
+		r.db.Collection("message_edits").DeleteOne(context.TODO(), bson.M{"_id": editID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("message %s was modified concurrently", messageID.Hex())
+}
+
+// GetMessageEdits 메시지 수정 이력 조회 (최신순)
+func (r *MessageRepository) GetMessageEdits(messageID primitive.ObjectID) ([]*models.MessageEdit, error) {
+	cursor, err := r.db.Collection("message_edits").Find(
+		context.TODO(),
+		bson.M{"message_id": messageID},
+		options.Find().SetSort(bson.D{{Key: "edited_at", Value: -1}, {Key: "_id", Value: -1}}),
 	)
 	if err != nil {
 		return nil, err
 	}
 	defer cursor.Close(context.TODO())
 
+	edits := []*models.MessageEdit{}
+	if err := cursor.All(context.TODO(), &edits); err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// DeleteMessage 메시지를 삭제 표시하고 내용, 첨부파일 정보, 수정 이력을 지움 (tombstone으로 남김)
+// retainUntil이 있으면 지우기 전에 원래 내용과 수정 이력을 그 시각까지 deleted_messages에 보관한다.
+// 이미 삭제된 메시지면 mongo.ErrNoDocuments를 반환한다.
+func (r *MessageRepository) DeleteMessage(messageID, deletedBy primitive.ObjectID, deletedAt int64, retainUntil time.Time) (*models.Message, error) {
+	var archiveID primitive.ObjectID
+	if !retainUntil.IsZero() {
+		var err error
+		archiveID, err = r.archiveDeletedMessage(messageID, deletedBy, deletedAt, retainUntil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var message models.Message
+	err := r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": messageID, "deleted_at": bson.M{"$exists": false}},
+		bson.M{
+			"$set":   bson.M{"content": "", "deleted_at": deletedAt, "deleted_by": deletedBy},
+			"$unset": bson.M{"reactions": "", "attachments": "", "mentions": "", "mentioned_user_ids": ""},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if err != nil {
+		if !archiveID.IsZero() {
+			// 그 사이 다른 요청이 먼저 삭제했으면 보관본이 중복되지 않게 지움
+			r.db.Collection("deleted_messages").DeleteOne(context.TODO(), bson.M{"_id": archiveID})
+		}
+		return nil, err
+	}
+
+	// 수정 이력과 멘션 보관함에도 원래 내용이 남아 있으므로 함께 삭제
+	for _, collection := range []string{"message_edits", "mentions"} {
+		if _, err := r.db.Collection(collection).DeleteMany(context.TODO(), bson.M{"message_id": messageID}); err != nil {
+			return nil, err
+		}
+	}
+	return &message, nil
+}
+
+// 삭제하기 전의 메시지 내용과 수정 이력 보관
+func (r *MessageRepository) archiveDeletedMessage(messageID, deletedBy primitive.ObjectID, deletedAt int64, retainUntil time.Time) (primitive.ObjectID, error) {
+	original, err := r.GetMessageByID(messageID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	if original.IsDeleted() {
+		return primitive.NilObjectID, mongo.ErrNoDocuments
+	}
+	edits, err := r.GetMessageEdits(messageID)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	result, err := r.db.Collection("deleted_messages").InsertOne(context.TODO(), &models.DeletedMessage{
+		MessageID: original.ID,
+		RoomID:    original.RoomID,
+		SenderID:  original.SenderID,
+		DeletedBy: deletedBy,
+		Content:   original.Content,
+		Edits:     edits,
+		CreatedAt: original.CreatedAt,
+		DeletedAt: deletedAt,
+		ExpiresAt: retainUntil,
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// AddReaction 메시지에 리액션 추가 후 갱신된 메시지 반환
+// 이미 같은 리액션을 누른 경우 조건에 맞는 문서가 없어 mongo.ErrNoDocuments를 반환한다.
+func (r *MessageRepository) AddReaction(messageID, userID primitive.ObjectID, emoji string) (*models.Message, error) {
+	field := "reactions." + emoji
+
+	var message models.Message
+	err := r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": messageID, "deleted_at": bson.M{"$exists": false}, field: bson.M{"$ne": userID}},
+		bson.M{"$addToSet": bson.M{field: userID}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// RemoveReaction 메시지에서 리액션 제거 후 갱신된 메시지 반환
+// 리액션을 누르지 않은 경우 mongo.ErrNoDocuments를 반환한다.
+func (r *MessageRepository) RemoveReaction(messageID, userID primitive.ObjectID, emoji string) (*models.Message, error) {
+	field := "reactions." + emoji
+
+	var message models.Message
+	err := r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": messageID, field: userID},
+		bson.M{"$pull": bson.M{field: userID}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+
+	// 아무도 누르지 않은 리액션은 정리 (그 사이 다시 추가되었으면 조건에 맞지 않아 유지됨)
+	if len(message.Reactions[emoji]) == 0 {
+		_, err := r.db.Collection("messages").UpdateOne(
+			context.TODO(),
+			bson.M{"_id": messageID, field: bson.M{"$size": 0}},
+			bson.M{"$unset": bson.M{field: ""}},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &message, nil
+}
+
+// UpdateMessageAttachment 메시지에 저장된 첨부파일 정보 갱신 후 갱신된 메시지 반환
+// 메시지가 없거나 삭제되어 첨부파일이 지워진 경우 mongo.ErrNoDocuments를 반환한다.
+func (r *MessageRepository) UpdateMessageAttachment(messageID primitive.ObjectID, attachment models.MessageAttachment) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Collection("messages").FindOneAndUpdate(
+		context.TODO(),
+		bson.M{"_id": messageID, "attachments.id": attachment.ID},
+		bson.M{"$set": bson.M{"attachments.$": attachment}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// UnreadCount 채팅방별 안 읽은 메시지 수와 그중 유저가 멘션된 메시지 수
+type UnreadCount struct {
+	RoomID   primitive.ObjectID `bson:"_id"`
+	Unread   int64              `bson:"unread"`
+	Mentions int64              `bson:"mentions"`
+}
+
+// CountUnreadMessagesByRoom 여러 채팅방의 안 읽은 메시지 수를 한 번의 aggregation으로 계산
+// 읽음 위치 이후 다른 유저가 보낸 메시지를 세며, 큰 채팅방에서도 비용이 일정하도록
+// 채팅방마다 최근 limit개까지만 센다. (멘션 수도 그 범위 안에서 셈)
+// 안 읽은 메시지가 없는 채팅방은 결과에 포함되지 않는다.
+func (r *MessageRepository) CountUnreadMessagesByRoom(userID primitive.ObjectID, roomIDs []primitive.ObjectID, positions map[primitive.ObjectID]*models.ReadPosition, limit int64) (map[primitive.ObjectID]UnreadCount, error) {
+	counts := map[primitive.ObjectID]UnreadCount{}
+	if len(roomIDs) == 0 {
+		return counts, nil
+	}
+
+	// 채팅방마다 인덱스(room_id, created_at)를 타는 하위 파이프라인을 만들어 $unionWith로 합침
+	roomPipeline := func(roomID primitive.ObjectID) bson.A {
+		filter := bson.M{
+			"room_id":    roomID,
+			"sender_id":  bson.M{"$ne": userID},
+			"deleted_at": bson.M{"$exists": false},
+		}
+		if position := positions[roomID]; position != nil {
+			filter["$or"] = bson.A{
+				bson.M{"created_at": bson.M{"$gt": position.LastReadAt}},
+				bson.M{"created_at": position.LastReadAt, "_id": bson.M{"$gt": position.LastReadMessageID}},
+			}
+		}
+		stages := bson.A{
+			bson.M{"$match": filter},
+			bson.M{"$sort": bson.M{"created_at": -1}},
+		}
+		if limit > 0 {
+			stages = append(stages, bson.M{"$limit": limit})
+		}
+		return append(stages, bson.M{"$project": bson.M{"room_id": 1, "mentioned_user_ids": 1}})
+	}
+
+	pipeline := roomPipeline(roomIDs[0])
+	for _, roomID := range roomIDs[1:] {
+		pipeline = append(pipeline, bson.M{"$unionWith": bson.M{"coll": "messages", "pipeline": roomPipeline(roomID)}})
+	}
+	pipeline = append(pipeline, bson.M{"$group": bson.M{
+		"_id":    "$room_id",
+		"unread": bson.M{"$sum": 1},
+		"mentions": bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$in": bson.A{userID, bson.M{"$ifNull": bson.A{"$mentioned_user_ids", bson.A{}}}}}, 1, 0,
+		}}},
+	}})
+
+	cursor, err := r.db.Collection("messages").Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	results := []UnreadCount{}
+	if err := cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+	for _, count := range results {
+		counts[count.RoomID] = count
+	}
+	return counts, nil
+}
+
+// GetLatestMessage 채팅방의 가장 최근 메시지 조회
+func (r *MessageRepository) GetLatestMessage(roomID primitive.ObjectID) (*models.Message, error) {
+	var message models.Message
+	err := r.db.Collection("messages").FindOne(
+		context.TODO(),
+		bson.M{"room_id": roomID},
+		options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}),
+	).Decode(&message)
+	if err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetMessagesByIDs 여러 메시지 조회 (ID 기준 맵)
+func (r *MessageRepository) GetMessagesByIDs(messageIDs []primitive.ObjectID) (map[primitive.ObjectID]*models.Message, error) {
+	cursor, err := r.db.Collection("messages").Find(context.TODO(), bson.M{"_id": bson.M{"$in": messageIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
 	var messages []*models.Message
 	if err := cursor.All(context.TODO(), &messages); err != nil {
 		return nil, err
 	}
 
-	return messages, nil
+	result := make(map[primitive.ObjectID]*models.Message, len(messages))
+	for _, message := range messages {
+		result[message.ID] = message
+	}
+	return result, nil
+}
+
+// EachMessage 삭제되지 않은 모든 메시지를 순회 (검색 색인 재구성용)
+func (r *MessageRepository) EachMessage(fn func(message *models.Message) error) error {
+	cursor, err := r.db.Collection("messages").Find(context.TODO(), bson.M{"deleted_at": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+
+	for cursor.Next(context.TODO()) {
+		var message models.Message
+		if err := cursor.Decode(&message); err != nil {
+			return err
+		}
+		if err := fn(&message); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// GetUsersByIDs 여러 유저의 이름과 이메일 조회 (멘션 대상 확인용)
+func (r *MessageRepository) GetUsersByIDs(userIDs []primitive.ObjectID) ([]*models.User, error) {
+	cursor, err := r.db.Collection("users").Find(
+		context.TODO(),
+		bson.M{"_id": bson.M{"$in": userIDs}},
+		options.Find().SetProjection(bson.M{"name": 1, "email": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	users := []*models.User{}
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindUserIDsByName 이름 또는 이메일이 일치하는 유저 ID 조회 (대소문자 무시)
+func (r *MessageRepository) FindUserIDsByName(name string) ([]primitive.ObjectID, error) {
+	pattern := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(name) + "$", Options: "i"}
+	cursor, err := r.db.Collection("users").Find(
+		context.TODO(),
+		bson.M{"$or": bson.A{bson.M{"name": pattern}, bson.M{"email": pattern}}},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var users []models.User
+	if err := cursor.All(context.TODO(), &users); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
+
+// InsertMentions 멘션 보관함에 추가 후 새로 추가된 멘션 반환
+// 같은 메시지에서 이미 멘션된 유저는 건너뛴다. (메시지 수정 시)
+func (r *MessageRepository) InsertMentions(mentions []*models.Mention) ([]*models.Mention, error) {
+	if len(mentions) == 0 {
+		return nil, nil
+	}
+
+	documents := make([]interface{}, 0, len(mentions))
+	for _, mention := range mentions {
+		mention.ID = primitive.NewObjectID()
+		documents = append(documents, mention)
+	}
+
+	// 순서 없이 한 번에 저장하고, 이미 있는 멘션(유니크 인덱스 충돌)만 건너뜀
+	_, err := r.db.Collection("mentions").InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return mentions, nil
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if !errors.As(err, &bulkErr) || bulkErr.WriteConcernError != nil {
+		return nil, err
+	}
+	failed := map[int]bool{}
+	err = nil
+	for _, writeErr := range bulkErr.WriteErrors {
+		failed[writeErr.Index] = true
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			err = writeErr
+		}
+	}
+
+	// 중복이 아닌 오류가 있어도 저장된 멘션은 함께 반환
+	inserted := make([]*models.Mention, 0, len(mentions))
+	for i, mention := range mentions {
+		if !failed[i] {
+			inserted = append(inserted, mention)
+		}
+	}
+	return inserted, err
+}
+
+// DeleteMentionsExcept 메시지 수정으로 더 이상 멘션되지 않은 유저의 멘션 삭제
+func (r *MessageRepository) DeleteMentionsExcept(messageID primitive.ObjectID, userIDs []primitive.ObjectID) error {
+	_, err := r.db.Collection("mentions").DeleteMany(context.TODO(), bson.M{
+		"message_id": messageID,
+		"user_id":    bson.M{"$nin": userIDs},
+	})
+	return err
+}
+
+// UpdateMentionSnippet 메시지 수정 시 멘션 보관함의 미리보기 갱신
+func (r *MessageRepository) UpdateMentionSnippet(messageID primitive.ObjectID, snippet string) error {
+	_, err := r.db.Collection("mentions").UpdateMany(
+		context.TODO(),
+		bson.M{"message_id": messageID},
+		bson.M{"$set": bson.M{"snippet": snippet}},
+	)
+	return err
+}
+
+// GetMentions 유저의 멘션 보관함 조회 (최신순, before가 nil이면 가장 최근부터)
+func (r *MessageRepository) GetMentions(userID primitive.ObjectID, unreadOnly bool, before *MessageAnchor, limit int64) ([]*models.Mention, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = bson.M{"$exists": false}
+	}
+	if before != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": before.CreatedAt}},
+			bson.M{"created_at": before.CreatedAt, "_id": bson.M{"$lt": before.ID}},
+		}
+	}
+
+	cursor, err := r.db.Collection("mentions").Find(
+		context.TODO(),
+		filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	mentions := []*models.Mention{}
+	if err := cursor.All(context.TODO(), &mentions); err != nil {
+		return nil, err
+	}
+	return mentions, nil
+}
+
+// CountUnreadMentions 읽지 않은 멘션 수 (limit까지만 셈, 0이면 제한 없음)
+func (r *MessageRepository) CountUnreadMentions(userID primitive.ObjectID, limit int64) (int64, error) {
+	countOptions := options.Count()
+	if limit > 0 {
+		countOptions.SetLimit(limit)
+	}
+	return r.db.Collection("mentions").CountDocuments(
+		context.TODO(),
+		bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}},
+		countOptions,
+	)
+}
+
+// MarkMentionRead 멘션 하나를 읽음 처리 (다른 유저의 멘션이면 mongo.ErrNoDocuments)
+func (r *MessageRepository) MarkMentionRead(userID, mentionID primitive.ObjectID, readAt int64) error {
+	result, err := r.db.Collection("mentions").UpdateOne(
+		context.TODO(),
+		bson.M{"_id": mentionID, "user_id": userID, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": readAt}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		// 이미 읽은 멘션은 성공으로 처리
+		count, err := r.db.Collection("mentions").CountDocuments(context.TODO(), bson.M{"_id": mentionID, "user_id": userID})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return mongo.ErrNoDocuments
+		}
+	}
+	return nil
+}
+
+// MarkMentionsRead 유저의 멘션을 읽음 처리 (roomID와 upTo가 있으면 해당 채팅방의 읽음 위치까지만)
+func (r *MessageRepository) MarkMentionsRead(userID primitive.ObjectID, roomID *primitive.ObjectID, upTo *MessageAnchor, readAt int64) error {
+	filter := bson.M{"user_id": userID, "read_at": bson.M{"$exists": false}}
+	if roomID != nil {
+		filter["room_id"] = *roomID
+	}
+	if upTo != nil {
+		filter["$or"] = bson.A{
+			bson.M{"created_at": bson.M{"$lt": upTo.CreatedAt}},
+			bson.M{"created_at": upTo.CreatedAt, "message_id": bson.M{"$lte": upTo.ID}},
+		}
+	}
+	_, err := r.db.Collection("mentions").UpdateMany(context.TODO(), filter, bson.M{"$set": bson.M{"read_at": readAt}})
+	return err
+}
+
+// DeleteUserMentionsInRoom 채팅방을 나간 유저의 멘션 삭제
+func (r *MessageRepository) DeleteUserMentionsInRoom(roomID, userID primitive.ObjectID) error {
+	_, err := r.db.Collection("mentions").DeleteMany(context.TODO(), bson.M{"room_id": roomID, "user_id": userID})
+	return err
 }
@@ -5,11 +5,13 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserRepository struct {
@@ -20,6 +22,35 @@ func NewUserRepository(db *mongo.Database) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// EnsureIndexes 사용자 관련 컬렉션 인덱스 생성
+func (r *UserRepository) EnsureIndexes() error {
+	_, err := r.db.Collection("login_history").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "family_id", Value: 1}},
+			// 패밀리 ID가 없는 이전 이력은 인덱스 대상에서 제외
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"family_id": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("password_resets").Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Collection("two_factor_challenges").Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "challenge_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
 // 사용자 조회
 func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 	var user models.User
@@ -34,26 +65,96 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 func (r *UserRepository) AddLoginHistory(history *models.LoginHistory) error {
 	history.CreatedAt = time.Now().Unix()
 	history.UpdatedAt = history.CreatedAt
+	history.LastUsedAt = history.CreatedAt
 	_, err := r.db.Collection("login_history").InsertOne(context.Background(), history)
 	return err
 }
 
-// 가장 최근 로그인 이력 가져오기
-func (r *UserRepository) GetLatestLoginHistory(userID primitive.ObjectID) (*models.LoginHistory, error) {
+// 토큰 패밀리 ID로 로그인 이력 조회
+func (r *UserRepository) FindLoginHistoryByFamilyID(familyID string) (*models.LoginHistory, error) {
 	var history models.LoginHistory
 	err := r.db.Collection("login_history").FindOne(
 		context.Background(),
-		bson.M{"user_id": userID},
+		bson.M{"family_id": familyID},
 	).Decode(&history)
-	return &history, err
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
 }
 
-// 로그인 이력 업데이트
-func (r *UserRepository) UpdateLoginHistory(id primitive.ObjectID, accessToken string) error {
-	_, err := r.db.Collection("login_history").UpdateOne(
+// 로그인 이력 업데이트 (토큰 회전)
+// oldRefreshToken이 여전히 현재 토큰인 경우에만 갱신하며, 갱신 여부를 반환한다.
+func (r *UserRepository) UpdateLoginHistory(id primitive.ObjectID, oldRefreshToken, accessToken, refreshToken string) (bool, error) {
+	now := time.Now().Unix()
+	result, err := r.db.Collection("login_history").UpdateOne(
 		context.Background(),
-		bson.M{"_id": id},
-		bson.M{"$set": bson.M{"access_token": accessToken, "updated_at": time.Now().Unix()}},
+		bson.M{"_id": id, "refresh_token": oldRefreshToken, "revoked_at": 0},
+		bson.M{"$set": bson.M{
+			"access_token":  accessToken,
+			"refresh_token": refreshToken,
+			"last_used_at":  now,
+			"updated_at":    now,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// 토큰 패밀리 전체 폐기
+func (r *UserRepository) RevokeLoginHistoryFamily(familyID string) error {
+	now := time.Now().Unix()
+	_, err := r.db.Collection("login_history").UpdateMany(
+		context.Background(),
+		bson.M{"family_id": familyID, "revoked_at": 0},
+		bson.M{"$set": bson.M{"revoked_at": now, "updated_at": now}},
+	)
+	return err
+}
+
+// 사용자의 활성 로그인 이력 목록 (since 이후 사용된 것만)
+func (r *UserRepository) GetActiveLoginHistories(userID primitive.ObjectID, since int64) ([]models.LoginHistory, error) {
+	var histories []models.LoginHistory
+
+	cursor, err := r.db.Collection("login_history").Find(
+		context.Background(),
+		bson.M{"user_id": userID, "revoked_at": 0, "last_used_at": bson.M{"$gte": since}},
+		options.Find().SetSort(bson.M{"last_used_at": -1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	if err := cursor.All(context.Background(), &histories); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// 사용자의 특정 로그인 이력 폐기 (해당 사용자의 이력이 아니면 false)
+func (r *UserRepository) RevokeUserLoginHistory(userID primitive.ObjectID, familyID string) (bool, error) {
+	now := time.Now().Unix()
+	result, err := r.db.Collection("login_history").UpdateOne(
+		context.Background(),
+		bson.M{"user_id": userID, "family_id": familyID, "revoked_at": 0},
+		bson.M{"$set": bson.M{"revoked_at": now, "updated_at": now}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// 사용자의 모든 로그인 이력 폐기
+func (r *UserRepository) RevokeAllLoginHistory(userID primitive.ObjectID) error {
+	now := time.Now().Unix()
+	_, err := r.db.Collection("login_history").UpdateMany(
+		context.Background(),
+		bson.M{"user_id": userID, "revoked_at": 0},
+		bson.M{"$set": bson.M{"revoked_at": now, "updated_at": now}},
 	)
 	return err
 }
@@ -93,6 +194,155 @@ func (r *UserRepository) FindEmailVerification(token string) (*models.EmailVerif
 	return &verification, err
 }
 
+func (r *UserRepository) AddPasswordReset(reset *models.PasswordReset) error {
+	reset.CreatedAt = time.Now().Unix()
+	reset.ExpiresAt = time.Now().Add(30 * time.Minute).Unix()
+	_, err := r.db.Collection("password_resets").InsertOne(context.Background(), reset)
+	return err
+}
+
+// 사용자의 미사용 비밀번호 재설정 토큰 무효화
+func (r *UserRepository) InvalidatePasswordResets(userID primitive.ObjectID) error {
+	_, err := r.db.Collection("password_resets").UpdateMany(
+		context.Background(),
+		bson.M{"user_id": userID, "used_at": 0},
+		bson.M{"$set": bson.M{"used_at": time.Now().Unix()}},
+	)
+	return err
+}
+
+// 비밀번호 재설정 토큰 사용 처리
+// 만료되지 않은 미사용 토큰만 원자적으로 사용 처리하여 한 번만 쓸 수 있도록 한다.
+func (r *UserRepository) ConsumePasswordReset(token string) (*models.PasswordReset, error) {
+	now := time.Now().Unix()
+	var reset models.PasswordReset
+	err := r.db.Collection("password_resets").FindOneAndUpdate(
+		context.Background(),
+		bson.M{"token": token, "used_at": 0, "expires_at": bson.M{"$gt": now}},
+		bson.M{"$set": bson.M{"used_at": now}},
+	).Decode(&reset)
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
+
+// 비밀번호 변경
+func (r *UserRepository) UpdatePassword(userID primitive.ObjectID, hashedPassword string) error {
+	result, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"password": hashedPassword, "updated_at": time.Now().Unix()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("no document found with userID: %s", userID.Hex())
+	}
+	return nil
+}
+
+// 2단계 인증 등록 대기 시크릿 저장
+func (r *UserRepository) SetTOTPPendingSecret(userID primitive.ObjectID, secret string) error {
+	_, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"totp_pending_secret": secret, "updated_at": time.Now().Unix()}},
+	)
+	return err
+}
+
+// 2단계 인증 활성화
+func (r *UserRepository) EnableTOTP(userID primitive.ObjectID, secret string, step int64, recoveryCodes []string) error {
+	_, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{
+			"$set": bson.M{
+				"totp_enabled":   true,
+				"totp_secret":    secret,
+				"totp_last_step": step,
+				"recovery_codes": recoveryCodes,
+				"updated_at":     time.Now().Unix(),
+			},
+			"$unset": bson.M{"totp_pending_secret": ""},
+		},
+	)
+	return err
+}
+
+// 2단계 인증 비활성화
+func (r *UserRepository) DisableTOTP(userID primitive.ObjectID) error {
+	_, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID},
+		bson.M{
+			"$set":   bson.M{"totp_enabled": false, "totp_last_step": 0, "updated_at": time.Now().Unix()},
+			"$unset": bson.M{"totp_secret": "", "totp_pending_secret": "", "recovery_codes": ""},
+		},
+	)
+	return err
+}
+
+// 마지막 사용 타임 스텝 갱신 (이미 사용된 스텝이면 false)
+func (r *UserRepository) UpdateTOTPLastStep(userID primitive.ObjectID, step int64) (bool, error) {
+	result, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID, "totp_last_step": bson.M{"$lt": step}},
+		bson.M{"$set": bson.M{"totp_last_step": step}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// 복구 코드 사용 처리 (없는 코드면 false)
+func (r *UserRepository) ConsumeRecoveryCode(userID primitive.ObjectID, codeHash string) (bool, error) {
+	result, err := r.db.Collection("users").UpdateOne(
+		context.Background(),
+		bson.M{"_id": userID, "recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"recovery_codes": codeHash}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// 2단계 로그인 챌린지 시도 횟수 증가 후 기록 반환 (첫 시도면 기록 생성)
+// 코드를 검증하기 전에 호출하여 동시에 여러 번 시도해도 횟수 제한을 넘지 못하게 한다.
+func (r *UserRepository) AddTwoFactorChallengeAttempt(challengeID string, userID primitive.ObjectID, expiresAt time.Time) (*models.TwoFactorChallenge, error) {
+	var challenge models.TwoFactorChallenge
+	err := r.db.Collection("two_factor_challenges").FindOneAndUpdate(
+		context.Background(),
+		bson.M{"challenge_id": challengeID},
+		bson.M{
+			"$inc":         bson.M{"attempts": 1},
+			"$setOnInsert": bson.M{"user_id": userID, "expires_at": expiresAt},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&challenge)
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// 2단계 로그인 챌린지 사용 처리 (이미 사용된 챌린지면 false)
+func (r *UserRepository) ConsumeTwoFactorChallenge(challengeID string, consumedAt int64) (bool, error) {
+	result, err := r.db.Collection("two_factor_challenges").UpdateOne(
+		context.Background(),
+		bson.M{"challenge_id": challengeID, "consumed_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"consumed_at": consumedAt}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
 func (r *UserRepository) MarkEmailVerified(userID primitive.ObjectID) error {
 	result, err := r.db.Collection("users").UpdateOne(
 		context.Background(),
@@ -117,6 +367,89 @@ func (r *UserRepository) MarkEmailVerified(userID primitive.ObjectID) error {
 	return nil
 }
 
+// 사용자 목록 조회 (이메일/이름 검색, 페이지네이션)
+func (r *UserRepository) ListUsers(query string, limit, page int64) ([]models.User, int64, error) {
+	filter := bson.M{}
+	if query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter["$or"] = bson.A{
+			bson.M{"email": pattern},
+			bson.M{"name": pattern},
+		}
+	}
+
+	total, err := r.db.Collection("users").CountDocuments(context.Background(), filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cursor, err := r.db.Collection("users").Find(
+		context.Background(),
+		filter,
+		options.Find().
+			SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+			SetSkip((page-1)*limit).
+			SetLimit(limit),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(context.Background())
+
+	users := []models.User{}
+	if err := cursor.All(context.Background(), &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// 사용자 역할 변경
+func (r *UserRepository) UpdateUserRole(userID primitive.ObjectID, role string) error {
+	return r.updateUser(userID, bson.M{"$set": bson.M{"role": role, "updated_at": time.Now().Unix()}})
+}
+
+// 계정 비활성화/재활성화
+func (r *UserRepository) SetUserDisabled(userID primitive.ObjectID, disabled bool) error {
+	now := time.Now().Unix()
+	if disabled {
+		return r.updateUser(userID, bson.M{"$set": bson.M{"is_disabled": true, "disabled_at": now, "updated_at": now}})
+	}
+	return r.updateUser(userID, bson.M{
+		"$set":   bson.M{"is_disabled": false, "updated_at": now},
+		"$unset": bson.M{"disabled_at": ""},
+	})
+}
+
+func (r *UserRepository) updateUser(userID primitive.ObjectID, update bson.M) error {
+	result, err := r.db.Collection("users").UpdateOne(context.Background(), bson.M{"_id": userID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// 사용자의 로그인 이력 조회 (최신순)
+func (r *UserRepository) GetLoginHistories(userID primitive.ObjectID, limit int64) ([]models.LoginHistory, error) {
+	cursor, err := r.db.Collection("login_history").Find(
+		context.Background(),
+		bson.M{"user_id": userID},
+		options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+
+	histories := []models.LoginHistory{}
+	if err := cursor.All(context.Background(), &histories); err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
 func (r *UserRepository) GetUserByID(userID primitive.ObjectID) (*models.User, error) {
 	var user models.User
 	err := r.db.Collection("users").FindOne(context.Background(), bson.M{"_id": userID}).Decode(&user)
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Tokenize 검색용 단어 분리 (소문자 변환, 문자와 숫자 이외는 구분자로 처리)
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+const (
+	maxSnippetLength = 200 // 하이라이트 결과의 최대 길이 (글자 수, 생략 부호 제외)
+	snippetContext   = 60  // 첫 번째 일치 단어 앞에 보여줄 글자 수
+)
+
+// Highlight 내용에서 검색어와 일치하는 단어를 <mark>로 감쌈
+// 긴 메시지는 첫 번째 일치 단어 주변만 잘라내고 잘린 쪽에 "…"를 붙인다.
+// 내용은 HTML 이스케이프되므로 클라이언트가 그대로 렌더링해도 안전하다.
+func Highlight(content string, terms []string) string {
+	runes := []rune(content)
+	start, end := snippetBounds(runes, terms)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		if !isWordRune(runes[i]) {
+			b.WriteString(html.EscapeString(string(runes[i])))
+			i++
+			continue
+		}
+
+		j := i
+		for j < end && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		if matchesAny(strings.ToLower(word), terms) {
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// 보여줄 범위 계산 (첫 번째 일치 단어가 포함되도록 하고 단어 중간에서 자르지 않음)
+func snippetBounds(runes []rune, terms []string) (int, int) {
+	if len(runes) <= maxSnippetLength {
+		return 0, len(runes)
+	}
+
+	matchStart, matchEnd := firstMatch(runes, terms)
+	start := matchStart - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := start + maxSnippetLength
+	if end > len(runes) {
+		end = len(runes)
+		start = end - maxSnippetLength
+	}
+
+	// 잘린 단어는 버림 (일치 단어는 유지)
+	for start > 0 && start < matchStart && isWordRune(runes[start-1]) && isWordRune(runes[start]) {
+		start++
+	}
+	for end < len(runes) && end > matchEnd && isWordRune(runes[end-1]) && isWordRune(runes[end]) {
+		end--
+	}
+	return start, end
+}
+
+// 첫 번째 일치 단어의 범위 (없으면 0, 0)
+func firstMatch(runes []rune, terms []string) (int, int) {
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		if matchesAny(strings.ToLower(string(runes[i:j])), terms) {
+			return i, j
+		}
+		i = j
+	}
+	return 0, 0
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if word == term {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		terms   []string
+		want    string
+	}{
+		{"no terms", "hello <world>", nil, "hello &lt;world&gt;"},
+		{"single match", "Hello world", []string{"hello"}, "<mark>Hello</mark> world"},
+		{"multiple terms", "go is fun, Go!", []string{"go", "fun"}, "<mark>go</mark> is <mark>fun</mark>, <mark>Go</mark>!"},
+		{"whole words only", "gopher go", []string{"go"}, "gopher <mark>go</mark>"},
+		{"escapes matched word neighbours", `<b>"deploy"</b>`, []string{"deploy"}, "&lt;b&gt;&#34;<mark>deploy</mark>&#34;&lt;/b&gt;"},
+		{"korean", "배포 완료했습니다", []string{"배포"}, "<mark>배포</mark> 완료했습니다"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.content, tt.terms); got != tt.want {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightLongContentWindow(t *testing.T) {
+	before := strings.Repeat("lorem ipsum ", 50)
+	after := strings.Repeat(" dolor sit amet", 50)
+	content := before + "needle" + after
+
+	got := Highlight(content, []string{"needle"})
+	if !strings.Contains(got, "<mark>needle</mark>") {
+		t.Fatalf("match missing from snippet: %q", got)
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("snippet should be elided on both sides: %q", got)
+	}
+
+	plain := strings.NewReplacer("<mark>", "", "</mark>", "", "…", "").Replace(got)
+	if n := utf8.RuneCountInString(plain); n > maxSnippetLength {
+		t.Errorf("snippet length = %d, want <= %d", n, maxSnippetLength)
+	}
+	// 단어 중간에서 자르지 않음
+	for _, word := range strings.Fields(plain) {
+		if !strings.Contains(" lorem ipsum needle dolor sit amet ", " "+word+" ") {
+			t.Errorf("snippet contains a cut word %q", word)
+		}
+	}
+}
+
+func TestHighlightLongContentEdges(t *testing.T) {
+	filler := strings.Repeat("word ", 100)
+
+	got := Highlight("needle "+filler, []string{"needle"})
+	if !strings.HasPrefix(got, "<mark>needle</mark>") || !strings.HasSuffix(got, "…") {
+		t.Errorf("match at start: %q", got)
+	}
+
+	got = Highlight(filler+"needle", []string{"needle"})
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "<mark>needle</mark>") {
+		t.Errorf("match at end: %q", got)
+	}
+
+	got = Highlight(filler, []string{"missing"})
+	if strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("no match should show the beginning: %q", got)
+	}
+}
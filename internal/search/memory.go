@@ -0,0 +1,196 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryIndex 프로세스 내 역색인 검색 구현체
+// DB 없이 동작하므로 테스트나 단일 인스턴스 개발 환경에서 사용한다.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[primitive.ObjectID]*indexedDocument
+	postings map[string]map[primitive.ObjectID]int // 단어 -> 메시지별 등장 횟수
+}
+
+type indexedDocument struct {
+	Document
+	terms map[string]int
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[primitive.ObjectID]*indexedDocument),
+		postings: make(map[string]map[primitive.ObjectID]int),
+	}
+}
+
+// Index 메시지 색인 (이미 있으면 교체)
+func (m *MemoryIndex) Index(doc Document) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(doc.ID)
+
+	terms := make(map[string]int)
+	for _, term := range Tokenize(doc.Content) {
+		terms[term]++
+	}
+	m.docs[doc.ID] = &indexedDocument{Document: doc, terms: terms}
+	for term, count := range terms {
+		if m.postings[term] == nil {
+			m.postings[term] = make(map[primitive.ObjectID]int)
+		}
+		m.postings[term][doc.ID] = count
+	}
+	return nil
+}
+
+// Remove 메시지 색인 삭제
+func (m *MemoryIndex) Remove(messageID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(messageID)
+	return nil
+}
+
+// RemoveRoom 채팅방의 모든 메시지 색인 삭제
+func (m *MemoryIndex) RemoveRoom(roomID primitive.ObjectID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for id, doc := range m.docs {
+		if doc.RoomID == roomID {
+			m.remove(id)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryIndex) remove(messageID primitive.ObjectID) {
+	doc, ok := m.docs[messageID]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		delete(m.postings[term], messageID)
+		if len(m.postings[term]) == 0 {
+			delete(m.postings, term)
+		}
+	}
+	delete(m.docs, messageID)
+}
+
+// Search 모든 검색어를 포함하는 메시지를 TF-IDF 점수순으로 검색 (같은 점수면 최신순)
+// 검색어가 없으면 필터에 맞는 메시지를 최신순으로 반환한다.
+func (m *MemoryIndex) Search(query Query) (*Result, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rooms := make(map[primitive.ObjectID]bool, len(query.RoomIDs))
+	for _, id := range query.RoomIDs {
+		rooms[id] = true
+	}
+	senders := make(map[primitive.ObjectID]bool, len(query.SenderIDs))
+	for _, id := range query.SenderIDs {
+		senders[id] = true
+	}
+
+	var hits []Hit
+	for _, doc := range m.candidates(query.Terms) {
+		if !rooms[doc.RoomID] || (len(senders) > 0 && !senders[doc.SenderID]) {
+			continue
+		}
+		if (query.After != 0 && doc.CreatedAt < query.After) || (query.Before != 0 && doc.CreatedAt >= query.Before) {
+			continue
+		}
+		if query.HasAttachment && !doc.HasAttachment {
+			continue
+		}
+
+		score, matched := m.score(doc, query.Terms)
+		if !matched {
+			continue
+		}
+		hits = append(hits, Hit{MessageID: doc.ID, Score: score})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		a, b := m.docs[hits[i].MessageID], m.docs[hits[j].MessageID]
+		if a.CreatedAt != b.CreatedAt {
+			return a.CreatedAt > b.CreatedAt
+		}
+		return a.ID.Hex() > b.ID.Hex()
+	})
+
+	result := &Result{Hits: []Hit{}, Total: int64(len(hits))}
+	start, end := pageBounds(int64(len(hits)), query.Limit, query.Page)
+	for _, hit := range hits[start:end] {
+		hit.Highlight = Highlight(m.docs[hit.MessageID].Content, query.Terms)
+		result.Hits = append(result.Hits, hit)
+	}
+	return result, nil
+}
+
+// 검색어 중 가장 드문 단어의 색인으로 후보를 좁힘 (검색어가 없으면 전체)
+func (m *MemoryIndex) candidates(terms []string) []*indexedDocument {
+	if len(terms) == 0 {
+		docs := make([]*indexedDocument, 0, len(m.docs))
+		for _, doc := range m.docs {
+			docs = append(docs, doc)
+		}
+		return docs
+	}
+
+	var rarest map[primitive.ObjectID]int
+	for i, term := range terms {
+		posting := m.postings[term]
+		if i == 0 || len(posting) < len(rarest) {
+			rarest = posting
+		}
+	}
+	docs := make([]*indexedDocument, 0, len(rarest))
+	for id := range rarest {
+		docs = append(docs, m.docs[id])
+	}
+	return docs
+}
+
+// TF-IDF 점수 계산 (검색어가 하나라도 없으면 matched가 false)
+func (m *MemoryIndex) score(doc *indexedDocument, terms []string) (float64, bool) {
+	score := 0.0
+	total := float64(len(m.docs))
+	for _, term := range terms {
+		count, ok := doc.terms[term]
+		if !ok {
+			return 0, false
+		}
+		idf := math.Log(1 + total/float64(len(m.postings[term])))
+		score += (1 + math.Log(float64(count))) * idf
+	}
+	return score, true
+}
+
+// 페이지 범위 계산
+func pageBounds(total, limit, page int64) (int64, int64) {
+	if limit <= 0 || page <= 0 {
+		return 0, 0
+	}
+	// 마지막 페이지를 넘으면 빈 범위 (큰 page 값의 곱셈 오버플로 방지)
+	if page-1 > total/limit {
+		return total, total
+	}
+	start := min((page-1)*limit, total)
+	end := start + limit
+	if end > total {
+		end = total
+	}
+	return start, end
+}
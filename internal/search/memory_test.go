@@ -0,0 +1,204 @@
+package search
+
+import (
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryFixture struct {
+	index   *MemoryIndex
+	general primitive.ObjectID
+	private primitive.ObjectID
+	alice   primitive.ObjectID
+	bob     primitive.ObjectID
+	docs    map[string]primitive.ObjectID
+}
+
+func newMemoryFixture(t *testing.T) *memoryFixture {
+	t.Helper()
+	f := &memoryFixture{
+		index:   NewMemoryIndex(),
+		general: primitive.NewObjectID(),
+		private: primitive.NewObjectID(),
+		alice:   primitive.NewObjectID(),
+		bob:     primitive.NewObjectID(),
+		docs:    map[string]primitive.ObjectID{},
+	}
+
+	add := func(name string, room, sender primitive.ObjectID, content string, createdAt int64, hasAttachment bool) {
+		doc := Document{
+			ID:            primitive.NewObjectID(),
+			RoomID:        room,
+			SenderID:      sender,
+			Content:       content,
+			CreatedAt:     createdAt,
+			HasAttachment: hasAttachment,
+		}
+		if err := f.index.Index(doc); err != nil {
+			t.Fatal(err)
+		}
+		f.docs[name] = doc.ID
+	}
+	add("deploy-twice", f.general, f.alice, "deploy deploy deploy to production", 100, false)
+	add("deploy-once", f.general, f.bob, "deploy finished", 200, true)
+	add("deploy-old", f.general, f.bob, "deploy finished", 150, false)
+	add("lunch", f.general, f.alice, "lunch time", 300, false)
+	add("secret-deploy", f.private, f.alice, "deploy secret keys", 400, false)
+	return f
+}
+
+func (f *memoryFixture) ids(t *testing.T, hits []Hit) []string {
+	t.Helper()
+	names := make(map[primitive.ObjectID]string, len(f.docs))
+	for name, id := range f.docs {
+		names[id] = name
+	}
+	result := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		result = append(result, names[hit.MessageID])
+	}
+	return result
+}
+
+func TestMemoryIndexSearch(t *testing.T) {
+	f := newMemoryFixture(t)
+
+	tests := []struct {
+		name      string
+		query     Query
+		want      []string
+		wantTotal int64
+	}{
+		{
+			name:      "ranked by term frequency then newest",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 10, Page: 1},
+			want:      []string{"deploy-twice", "deploy-once", "deploy-old"},
+			wantTotal: 3,
+		},
+		{
+			name:      "inaccessible rooms are excluded",
+			query:     Query{Terms: []string{"secret"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 10, Page: 1},
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "no rooms means no results",
+			query:     Query{Terms: []string{"deploy"}, Limit: 10, Page: 1},
+			want:      []string{},
+			wantTotal: 0,
+		},
+		{
+			name:      "all terms must match",
+			query:     Query{Terms: []string{"deploy", "finished"}, RoomIDs: []primitive.ObjectID{f.general, f.private}, Limit: 10, Page: 1},
+			want:      []string{"deploy-once", "deploy-old"},
+			wantTotal: 2,
+		},
+		{
+			name:      "sender filter",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general, f.private}, SenderIDs: []primitive.ObjectID{f.alice}, Limit: 10, Page: 1},
+			want:      []string{"deploy-twice", "secret-deploy"},
+			wantTotal: 2,
+		},
+		{
+			name:      "date range is inclusive after, exclusive before",
+			query:     Query{RoomIDs: []primitive.ObjectID{f.general}, After: 150, Before: 300, Limit: 10, Page: 1},
+			want:      []string{"deploy-once", "deploy-old"},
+			wantTotal: 2,
+		},
+		{
+			name:      "attachment filter",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general}, HasAttachment: true, Limit: 10, Page: 1},
+			want:      []string{"deploy-once"},
+			wantTotal: 1,
+		},
+		{
+			name:      "filters only are ordered newest first",
+			query:     Query{RoomIDs: []primitive.ObjectID{f.general}, SenderIDs: []primitive.ObjectID{f.alice}, Limit: 10, Page: 1},
+			want:      []string{"lunch", "deploy-twice"},
+			wantTotal: 2,
+		},
+		{
+			name:      "second page",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 2, Page: 2},
+			want:      []string{"deploy-old"},
+			wantTotal: 3,
+		},
+		{
+			name:      "page past the end",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 2, Page: 5},
+			want:      []string{},
+			wantTotal: 3,
+		},
+		{
+			name:      "page large enough to overflow the offset",
+			query:     Query{Terms: []string{"deploy"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 20, Page: math.MaxInt64},
+			want:      []string{},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := f.index.Search(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := f.ids(t, result.Hits)
+			if len(got) != len(tt.want) {
+				t.Fatalf("hits = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("hits = %v, want %v", got, tt.want)
+				}
+			}
+			if result.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", result.Total, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexSearchHighlights(t *testing.T) {
+	f := newMemoryFixture(t)
+
+	result, err := f.index.Search(Query{Terms: []string{"lunch"}, RoomIDs: []primitive.ObjectID{f.general}, Limit: 10, Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 1 || result.Hits[0].Highlight != "<mark>lunch</mark> time" {
+		t.Fatalf("unexpected hits %+v", result.Hits)
+	}
+}
+
+func TestMemoryIndexReindexAndRemove(t *testing.T) {
+	f := newMemoryFixture(t)
+	rooms := []primitive.ObjectID{f.general, f.private}
+
+	// 수정된 메시지는 새 내용으로만 검색됨
+	if err := f.index.Index(Document{ID: f.docs["lunch"], RoomID: f.general, SenderID: f.alice, Content: "dinner time", CreatedAt: 300}); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := f.index.Search(Query{Terms: []string{"lunch"}, RoomIDs: rooms, Limit: 10, Page: 1}); result.Total != 0 {
+		t.Errorf("old content still indexed: %+v", result.Hits)
+	}
+	if result, _ := f.index.Search(Query{Terms: []string{"dinner"}, RoomIDs: rooms, Limit: 10, Page: 1}); result.Total != 1 {
+		t.Errorf("new content not indexed: %+v", result.Hits)
+	}
+
+	if err := f.index.Remove(f.docs["deploy-twice"]); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.index.RemoveRoom(f.private); err != nil {
+		t.Fatal(err)
+	}
+	result, err := f.index.Search(Query{Terms: []string{"deploy"}, RoomIDs: rooms, Limit: 10, Page: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.ids(t, result.Hits); len(got) != 2 || got[0] != "deploy-once" || got[1] != "deploy-old" {
+		t.Errorf("hits after removal = %v", got)
+	}
+}
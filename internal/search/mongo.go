@@ -0,0 +1,121 @@
+package search
+
+import (
+	"chat-go-api/internal/models"
+	"context"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoSearcher messages 컬렉션의 텍스트 인덱스를 사용하는 검색 구현체
+// 메시지 컬렉션 자체가 색인이므로 Index, Remove는 아무것도 하지 않는다.
+type MongoSearcher struct {
+	db *mongo.Database
+}
+
+func NewMongoSearcher(db *mongo.Database) *MongoSearcher {
+	return &MongoSearcher{db: db}
+}
+
+// EnsureIndexes 메시지 내용 텍스트 인덱스 생성
+// 한국어는 형태소 분석이 지원되지 않으므로 언어를 none으로 두어 단어 단위로 색인한다.
+func (s *MongoSearcher) EnsureIndexes() error {
+	_, err := s.db.Collection("messages").Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "content", Value: "text"}},
+		Options: options.Index().SetDefaultLanguage("none").SetName("content_text"),
+	})
+	return err
+}
+
+func (s *MongoSearcher) Index(doc Document) error {
+	return nil
+}
+
+func (s *MongoSearcher) Remove(messageID primitive.ObjectID) error {
+	return nil
+}
+
+func (s *MongoSearcher) RemoveRoom(roomID primitive.ObjectID) error {
+	return nil
+}
+
+// Search 텍스트 점수순으로 검색 (검색어가 없으면 최신순)
+func (s *MongoSearcher) Search(query Query) (*Result, error) {
+	filter := bson.M{
+		"room_id":    bson.M{"$in": query.RoomIDs},
+		"deleted_at": bson.M{"$exists": false},
+		"type":       bson.M{"$ne": models.MessageTypeSystem},
+	}
+	if len(query.SenderIDs) > 0 {
+		filter["sender_id"] = bson.M{"$in": query.SenderIDs}
+	}
+	createdAt := bson.M{}
+	if query.After != 0 {
+		createdAt["$gte"] = query.After
+	}
+	if query.Before != 0 {
+		createdAt["$lt"] = query.Before
+	}
+	if len(createdAt) > 0 {
+		filter["created_at"] = createdAt
+	}
+	if query.HasAttachment {
+		filter["attachments.0"] = bson.M{"$exists": true}
+	}
+
+	findOptions := options.Find().
+		SetProjection(bson.M{"content": 1}).
+		SetLimit(query.Limit)
+	if len(query.Terms) > 0 {
+		// 각 검색어를 따옴표로 감싸 모든 검색어를 포함하는 메시지만 찾음
+		search := ""
+		for _, term := range query.Terms {
+			search += strconv.Quote(term) + " "
+		}
+		filter["$text"] = bson.M{"$search": search}
+		findOptions.SetProjection(bson.M{"content": 1, "score": bson.M{"$meta": "textScore"}})
+		findOptions.SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}})
+	} else {
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	}
+
+	total, err := s.db.Collection("messages").CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// 마지막 페이지를 넘으면 조회하지 않음 (큰 page 값의 곱셈 오버플로 방지)
+	if query.Limit <= 0 || query.Page <= 0 || query.Page-1 > total/query.Limit {
+		return &Result{Hits: []Hit{}, Total: total}, nil
+	}
+	findOptions.SetSkip((query.Page - 1) * query.Limit)
+
+	cursor, err := s.db.Collection("messages").Find(context.TODO(), filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.TODO())
+
+	var docs []struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Content string             `bson:"content"`
+		Score   float64            `bson:"score"`
+	}
+	if err := cursor.All(context.TODO(), &docs); err != nil {
+		return nil, err
+	}
+
+	result := &Result{Hits: []Hit{}, Total: total}
+	for _, doc := range docs {
+		result.Hits = append(result.Hits, Hit{
+			MessageID: doc.ID,
+			Score:     doc.Score,
+			Highlight: Highlight(doc.Content, query.Terms),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// ParsedQuery 사용자가 입력한 검색어를 해석한 결과
+// from:, in: 값은 유저/채팅방 ID 또는 이름 그대로 담긴다.
+type ParsedQuery struct {
+	Terms         []string
+	From          []string
+	In            []string
+	After         int64
+	Before        int64
+	HasAttachment bool
+}
+
+// IsEmpty 검색어와 필터가 모두 없는지 여부
+func (q *ParsedQuery) IsEmpty() bool {
+	return len(q.Terms) == 0 && len(q.From) == 0 && len(q.In) == 0 &&
+		q.After == 0 && q.Before == 0 && !q.HasAttachment
+}
+
+// ParseQuery 검색어 해석
+// 지원하는 필터: from:<유저>, in:<채팅방>, after:YYYY-MM-DD, before:YYYY-MM-DD, on:YYYY-MM-DD, has:attachment
+// 필터 값에 공백이 있으면 from:"홍 길동"처럼 따옴표로 감싼다.
+func ParseQuery(raw string) (*ParsedQuery, error) {
+	query := &ParsedQuery{}
+	for _, token := range splitQuery(raw) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			query.Terms = append(query.Terms, Tokenize(token)...)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "from":
+			query.From = append(query.From, value)
+		case "in":
+			query.In = append(query.In, value)
+		case "after", "before", "on":
+			day, err := time.ParseInLocation(dateLayout, value, time.UTC)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid date %q", ErrInvalidQuery, value)
+			}
+			switch strings.ToLower(key) {
+			case "after":
+				query.After = day.Unix()
+			case "before":
+				query.Before = day.Unix()
+			default:
+				query.After, query.Before = day.Unix(), day.AddDate(0, 0, 1).Unix()
+			}
+		case "has":
+			if !strings.EqualFold(value, "attachment") && !strings.EqualFold(value, "file") {
+				return nil, fmt.Errorf("%w: unsupported filter has:%s", ErrInvalidQuery, value)
+			}
+			query.HasAttachment = true
+		default:
+			// 알 수 없는 필터는 일반 검색어로 처리 (예: 시각 "12:30")
+			query.Terms = append(query.Terms, Tokenize(token)...)
+		}
+	}
+
+	if query.After != 0 && query.Before != 0 && query.After >= query.Before {
+		return nil, fmt.Errorf("%w: empty date range", ErrInvalidQuery)
+	}
+	return query, nil
+}
+
+// 공백 기준으로 나누되 따옴표로 감싼 부분은 하나로 유지
+func splitQuery(raw string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case !inQuotes && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
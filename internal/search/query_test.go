@@ -0,0 +1,83 @@
+package search
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(value string) int64 {
+	t, err := time.ParseInLocation(dateLayout, value, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t.Unix()
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want ParsedQuery
+	}{
+		{"empty", "", ParsedQuery{}},
+		{"terms", "Deploy  failed!", ParsedQuery{Terms: []string{"deploy", "failed"}}},
+		{"from", "from:alice build", ParsedQuery{Terms: []string{"build"}, From: []string{"alice"}}},
+		{"quoted from", `from:"홍 길동" 배포`, ParsedQuery{Terms: []string{"배포"}, From: []string{"홍 길동"}}},
+		{"multiple in", "in:general in:random", ParsedQuery{In: []string{"general", "random"}}},
+		{"filter key is case insensitive", "FROM:bob", ParsedQuery{From: []string{"bob"}}},
+		{"after", "after:2024-03-01", ParsedQuery{After: day("2024-03-01")}},
+		{"before", "before:2024-03-01", ParsedQuery{Before: day("2024-03-01")}},
+		{"date range", "after:2024-03-01 before:2024-03-10", ParsedQuery{After: day("2024-03-01"), Before: day("2024-03-10")}},
+		{"on", "on:2024-03-01", ParsedQuery{After: day("2024-03-01"), Before: day("2024-03-02")}},
+		{"has attachment", "has:attachment report", ParsedQuery{Terms: []string{"report"}, HasAttachment: true}},
+		{"has file", "has:FILE", ParsedQuery{HasAttachment: true}},
+		{"unknown filter is a term", "meet 12:30", ParsedQuery{Terms: []string{"meet", "12", "30"}}},
+		{"empty filter value is a term", "from:", ParsedQuery{Terms: []string{"from"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.raw)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q): %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseQuery(%q) = %+v, want %+v", tt.raw, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, raw := range []string{
+		"after:yesterday",
+		"before:2024-13-01",
+		"has:link",
+		"after:2024-03-10 before:2024-03-01",
+		"after:2024-03-01 before:2024-03-01",
+	} {
+		if _, err := ParseQuery(raw); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("ParseQuery(%q): got %v, want ErrInvalidQuery", raw, err)
+		}
+	}
+}
+
+func TestParsedQueryIsEmpty(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"":               true,
+		"   ":            true,
+		"hello":          false,
+		"from:alice":     false,
+		"has:attachment": false,
+	} {
+		query, err := ParseQuery(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := query.IsEmpty(); got != want {
+			t.Errorf("ParseQuery(%q).IsEmpty() = %v, want %v", raw, got, want)
+		}
+	}
+}
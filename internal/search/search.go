@@ -0,0 +1,54 @@
+package search
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Document 검색 대상 메시지
+type Document struct {
+	ID            primitive.ObjectID
+	RoomID        primitive.ObjectID
+	SenderID      primitive.ObjectID
+	Content       string
+	CreatedAt     int64
+	HasAttachment bool
+}
+
+// Query 검색 조건 (이름 등은 호출하는 쪽에서 ID로 변환한 상태)
+// RoomIDs는 필수이며, 검색하는 유저가 접근할 수 있는 채팅방으로 제한해야 한다.
+type Query struct {
+	Terms         []string
+	RoomIDs       []primitive.ObjectID
+	SenderIDs     []primitive.ObjectID // 비어 있으면 작성자 제한 없음
+	After         int64                // 이 시각 이후 (unix, 0이면 제한 없음)
+	Before        int64                // 이 시각 이전 (unix, 0이면 제한 없음)
+	HasAttachment bool
+	Limit         int64
+	Page          int64
+}
+
+// Hit 검색 결과 항목
+type Hit struct {
+	MessageID primitive.ObjectID
+	Score     float64
+	Highlight string // 검색어를 <mark>로 감싼 내용 일부 (HTML 이스케이프됨)
+}
+
+// Result 검색 결과 페이지
+type Result struct {
+	Hits  []Hit
+	Total int64
+}
+
+// Searcher 메시지 검색 구현체
+// 저장/수정/삭제 시 Index, Remove가 호출되며, DB 자체를 색인으로 쓰는 구현체는 무시해도 된다.
+type Searcher interface {
+	Index(doc Document) error
+	Remove(messageID primitive.ObjectID) error
+	RemoveRoom(roomID primitive.ObjectID) error
+	Search(query Query) (*Result, error)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"chat-go-api/internal/utils"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const adminLoginHistoryLimit = 50
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrInvalidRole      = errors.New("invalid role")
+	ErrCannotTargetSelf = errors.New("cannot perform this action on your own account")
+)
+
+// UserDetail 관리자용 사용자 상세 정보
+type UserDetail struct {
+	User         *models.UserDTO      `json:"user"`
+	LoginHistory []*models.SessionDTO `json:"login_history"`
+}
+
+type AdminService struct {
+	userRepo    *repository.UserRepository
+	authService *AuthService
+}
+
+func NewAdminService(userRepo *repository.UserRepository, authService *AuthService) *AdminService {
+	return &AdminService{userRepo: userRepo, authService: authService}
+}
+
+// ListUsers 사용자 목록 검색
+func (s *AdminService) ListUsers(query string, limit, page int64) ([]*models.UserDTO, int64, error) {
+	users, total, err := s.userRepo.ListUsers(query, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	userDTOs := []*models.UserDTO{}
+	for i := range users {
+		userDTOs = append(userDTOs, utils.ToUserDTO(&users[i]))
+	}
+	return userDTOs, total, nil
+}
+
+// GetUserDetail 사용자 상세 정보와 로그인 이력 조회
+func (s *AdminService) GetUserDetail(userID string) (*UserDetail, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := s.userRepo.GetLoginHistories(user.ID, adminLoginHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	loginHistory := []*models.SessionDTO{}
+	for i := range histories {
+		loginHistory = append(loginHistory, utils.ToSessionDTO(&histories[i], ""))
+	}
+
+	return &UserDetail{User: utils.ToUserDTO(user), LoginHistory: loginHistory}, nil
+}
+
+// ChangeRole 사용자 역할 변경
+func (s *AdminService) ChangeRole(actorID, userID, role string) error {
+	if !models.IsValidRole(role) {
+		return ErrInvalidRole
+	}
+	if actorID == userID {
+		return ErrCannotTargetSelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.UpdateUserRole(user.ID, role)
+}
+
+// VerifyEmail 이메일 인증 강제 처리
+func (s *AdminService) VerifyEmail(userID string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified {
+		return nil
+	}
+	return s.userRepo.MarkEmailVerified(user.ID)
+}
+
+// DisableUser 계정 비활성화 (모든 세션도 폐기)
+func (s *AdminService) DisableUser(actorID, userID string) error {
+	if actorID == userID {
+		return ErrCannotTargetSelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.SetUserDisabled(user.ID, true); err != nil {
+		return err
+	}
+	return s.authService.LogoutAll(userID)
+}
+
+// EnableUser 계정 재활성화
+func (s *AdminService) EnableUser(userID string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.SetUserDisabled(user.ID, false)
+}
+
+// ForceLogout 사용자의 모든 세션 강제 종료
+func (s *AdminService) ForceLogout(userID string) error {
+	if _, err := s.getUser(userID); err != nil {
+		return err
+	}
+	return s.authService.LogoutAll(userID)
+}
+
+func (s *AdminService) getUser(userID string) (*models.User, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user, err := s.userRepo.GetUserByID(id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
@@ -2,11 +2,12 @@ package services
 
 import (
 	"chat-go-api/internal/models"
-	"chat-go-api/internal/repository"
+	"chat-go-api/internal/utils"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -14,27 +15,111 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	accessTokenTTL  = 5 * time.Hour
+	refreshTokenTTL = 8 * time.Hour
+
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected. all sessions of this login have been revoked")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidResetToken   = errors.New("invalid or expired reset token")
+	ErrAccountDisabled     = errors.New("account is disabled")
+)
+
+// LoginResult 로그인 결과
+// 2단계 인증이 필요한 경우 토큰 대신 ChallengeToken만 채워진다.
+type LoginResult struct {
+	AccessToken    string
+	RefreshToken   string
+	ChallengeToken string
+}
+
+// TokenClaims 서비스에서 사용하는 JWT 클레임
+type TokenClaims struct {
+	UserID   string
+	FamilyID string
+	Type     string
+	Role     string
+}
+
+// SessionDisconnector 폐기된 세션의 실시간 연결을 종료
+type SessionDisconnector interface {
+	DisconnectSession(sessionID string)
+	DisconnectUser(userID string)
+}
+
+// UserStore 인증 서비스가 사용하는 유저 저장소 (repository.UserRepository)
+type UserStore interface {
+	FindByEmail(email string) (*models.User, error)
+	GetUserByID(userID primitive.ObjectID) (*models.User, error)
+	CreateUser(user *models.User) error
+	UpdatePassword(userID primitive.ObjectID, hashedPassword string) error
+
+	AddLoginHistory(history *models.LoginHistory) error
+	FindLoginHistoryByFamilyID(familyID string) (*models.LoginHistory, error)
+	UpdateLoginHistory(id primitive.ObjectID, oldRefreshToken, accessToken, refreshToken string) (bool, error)
+	RevokeLoginHistoryFamily(familyID string) error
+	GetActiveLoginHistories(userID primitive.ObjectID, since int64) ([]models.LoginHistory, error)
+	RevokeUserLoginHistory(userID primitive.ObjectID, familyID string) (bool, error)
+	RevokeAllLoginHistory(userID primitive.ObjectID) error
+
+	AddEmailVerification(verification *models.EmailVerification) error
+	FindEmailVerification(token string) (*models.EmailVerification, error)
+	MarkEmailVerified(userID primitive.ObjectID) error
+	AddPasswordReset(reset *models.PasswordReset) error
+	InvalidatePasswordResets(userID primitive.ObjectID) error
+	ConsumePasswordReset(token string) (*models.PasswordReset, error)
+
+	SetTOTPPendingSecret(userID primitive.ObjectID, secret string) error
+	EnableTOTP(userID primitive.ObjectID, secret string, step int64, recoveryCodes []string) error
+	DisableTOTP(userID primitive.ObjectID) error
+	UpdateTOTPLastStep(userID primitive.ObjectID, step int64) (bool, error)
+	ConsumeRecoveryCode(userID primitive.ObjectID, codeHash string) (bool, error)
+	AddTwoFactorChallengeAttempt(challengeID string, userID primitive.ObjectID, expiresAt time.Time) (*models.TwoFactorChallenge, error)
+	ConsumeTwoFactorChallenge(challengeID string, consumedAt int64) (bool, error)
+}
+
 type AuthService struct {
-	repo          *repository.UserRepository
+	repo          UserStore
 	accessSecret  string
 	refreshSecret string
 	emailService  *EmailService // 이메일 서비스 추가
+	sessions      SessionDisconnector
+	now           func() time.Time // 토큰 발급과 2단계 인증 코드 검증용 시계
 }
 
-func NewAuthService(repo *repository.UserRepository, emailService *EmailService, accessSecret, refreshSecret string) *AuthService {
+func NewAuthService(repo UserStore, emailService *EmailService, sessions SessionDisconnector, accessSecret, refreshSecret string) *AuthService {
 	return &AuthService{
 		repo:          repo,
 		accessSecret:  accessSecret,
 		refreshSecret: refreshSecret,
 		emailService:  emailService, // 이메일 서비스 초기화
+		sessions:      sessions,
+		now:           time.Now,
 	}
 }
 
 // 로그인 처리
-func (s *AuthService) Login(email, password string) (string, string, error) {
+func (s *AuthService) Login(email, password, userAgent, ipAddress string) (*LoginResult, error) {
 	user, err := s.repo.FindByEmail(email)
 	if err != nil {
-		return "", "", errors.New("user not found")
+		return nil, errors.New("user not found")
+	}
+
+	// 비밀번호 검증
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, errors.New("invalid password")
+	}
+
+	// 비활성화된 계정 확인 (비활성화된 계정에는 인증 이메일도 다시 보내지 않음)
+	if user.IsDisabled {
+		return nil, ErrAccountDisabled
 	}
 
 	// 이메일 인증 여부 확인
@@ -43,7 +128,7 @@ func (s *AuthService) Login(email, password string) (string, string, error) {
 			// 인증 이메일 다시 발송
 			token, err := s.generateVerificationToken()
 			if err != nil {
-				return "", "", fmt.Errorf("failed to generate verification token: %v", err)
+				return nil, fmt.Errorf("failed to generate verification token: %v", err)
 			}
 
 			verification := &models.EmailVerification{
@@ -54,82 +139,275 @@ func (s *AuthService) Login(email, password string) (string, string, error) {
 			}
 
 			if err := s.repo.AddEmailVerification(verification); err != nil {
-				return "", "", fmt.Errorf("failed to save verification token: %v", err)
+				return nil, fmt.Errorf("failed to save verification token: %v", err)
 			}
 
 			if err := s.emailService.SendVerificationEmailAsync(email, token); err != nil {
-				return "", "", err
+				return nil, err
 			}
 
-			return "", "", errors.New("email not verified. verification email resent")
+			return nil, errors.New("email not verified. verification email resent")
 		}
 	}
 
-	// 비밀번호 검증
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", "", errors.New("invalid password")
+	// 2단계 인증이 활성화된 경우 챌린지 토큰만 발급
+	if user.TOTPEnabled {
+		challengeToken, err := s.generateChallengeToken(user.ID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{ChallengeToken: challengeToken}, nil
 	}
 
-	// 토큰 생성
-	accessToken, err := s.generateToken(user.ID.Hex(), s.accessSecret, 5*time.Hour)
+	return s.createSession(user, userAgent, ipAddress)
+}
+
+// 새 세션(토큰 패밀리) 생성 및 로그인 이력 저장
+func (s *AuthService) createSession(user *models.User, userAgent, ipAddress string) (*LoginResult, error) {
+	if user.IsDisabled {
+		return nil, ErrAccountDisabled
+	}
+
+	// 토큰 패밀리 생성
+	familyID, err := randomHex(16)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
-	refreshToken, err := s.generateToken(user.ID.Hex(), s.refreshSecret, 8*time.Hour)
+
+	// 토큰 생성
+	accessToken, refreshToken, err := s.generateTokenPair(user, familyID)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	// 로그인 이력 저장
 	history := &models.LoginHistory{
 		UserID:       user.ID,
+		FamilyID:     familyID,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		UserAgent:    userAgent,
+		IPAddress:    ipAddress,
 	}
 	if err := s.repo.AddLoginHistory(history); err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return accessToken, refreshToken, nil
+	return &LoginResult{AccessToken: accessToken, RefreshToken: refreshToken}, nil
 }
 
 // 토큰 재발급
-func (s *AuthService) RefreshToken(userID string, oldRefreshToken string) (string, error) {
-	id, _ := primitive.ObjectIDFromHex(userID)
-	history, err := s.repo.GetLatestLoginHistory(id)
+// refresh 토큰은 매번 회전되며, 이미 사용된 refresh 토큰이 다시 제시되면 해당 토큰 패밀리 전체를 폐기한다.
+func (s *AuthService) RefreshToken(oldRefreshToken string) (string, string, error) {
+	claims, err := s.parseToken(oldRefreshToken, s.refreshSecret, tokenTypeRefresh)
 	if err != nil {
-		return "", errors.New("no login history found")
+		return "", "", ErrInvalidRefreshToken
 	}
 
-	// Refresh 토큰 검증
+	history, err := s.repo.FindLoginHistoryByFamilyID(claims.FamilyID)
+	if err != nil {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if history.UserID.Hex() != claims.UserID {
+		return "", "", ErrInvalidRefreshToken
+	}
+	if history.RevokedAt != 0 {
+		return "", "", ErrSessionRevoked
+	}
+
+	// 현재 토큰이 아니라면 이전에 회전된 토큰의 재사용
 	if history.RefreshToken != oldRefreshToken {
-		return "", errors.New("invalid refresh token")
+		return "", "", s.revokeReusedFamily(claims.FamilyID)
 	}
 
-	// 새로운 Access 토큰 생성
-	newAccessToken, err := s.generateToken(userID, s.accessSecret, 5*time.Minute)
+	// 새로운 토큰 쌍 생성 (역할 변경이 반영되도록 사용자를 다시 조회)
+	user, err := s.repo.GetUserByID(history.UserID)
 	if err != nil {
-		return "", err
+		return "", "", ErrInvalidRefreshToken
+	}
+	if user.IsDisabled {
+		return "", "", ErrAccountDisabled
+	}
+	newAccessToken, newRefreshToken, err := s.generateTokenPair(user, claims.FamilyID)
+	if err != nil {
+		return "", "", err
 	}
 
-	// 이력 업데이트
-	if err := s.repo.UpdateLoginHistory(history.ID, newAccessToken); err != nil {
-		return "", err
+	// 이력 업데이트 (동시에 같은 토큰으로 회전을 시도한 경우에도 재사용으로 간주)
+	rotated, err := s.repo.UpdateLoginHistory(history.ID, oldRefreshToken, newAccessToken, newRefreshToken)
+	if err != nil {
+		return "", "", err
+	}
+	if !rotated {
+		return "", "", s.revokeReusedFamily(claims.FamilyID)
+	}
+
+	return newAccessToken, newRefreshToken, nil
+}
+
+// Access 토큰 검증
+// 서명과 만료뿐 아니라 토큰이 속한 세션(토큰 패밀리)이 폐기되지 않았는지, 계정이 비활성화되지 않았는지도 확인한다.
+func (s *AuthService) ValidateAccessToken(tokenString string) (*TokenClaims, error) {
+	claims, err := s.parseToken(tokenString, s.accessSecret, tokenTypeAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.FindLoginHistoryByFamilyID(claims.FamilyID)
+	if err != nil || history.UserID.Hex() != claims.UserID {
+		return nil, errors.New("invalid token")
+	}
+	if history.RevokedAt != 0 {
+		return nil, ErrSessionRevoked
+	}
+
+	// 비활성화 여부와 최신 역할 확인
+	user, err := s.repo.GetUserByID(history.UserID)
+	if err != nil {
+		return nil, errors.New("invalid token")
 	}
+	if user.IsDisabled {
+		return nil, ErrAccountDisabled
+	}
+	claims.Role = user.Role
+
+	return claims, nil
+}
 
-	return newAccessToken, nil
+// 현재 세션 로그아웃
+func (s *AuthService) Logout(sessionID string) error {
+	if err := s.repo.RevokeLoginHistoryFamily(sessionID); err != nil {
+		return err
+	}
+	s.sessions.DisconnectSession(sessionID)
+	return nil
+}
+
+// 모든 세션 로그아웃
+func (s *AuthService) LogoutAll(userID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.RevokeAllLoginHistory(id); err != nil {
+		return err
+	}
+	s.sessions.DisconnectUser(userID)
+	return nil
+}
+
+// 활성 세션 목록 조회
+func (s *AuthService) GetSessions(userID, currentSessionID string) ([]*models.SessionDTO, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// refresh 토큰이 만료된 세션은 제외
+	since := time.Now().Add(-refreshTokenTTL).Unix()
+	histories, err := s.repo.GetActiveLoginHistories(id, since)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*models.SessionDTO{}
+	for i := range histories {
+		sessions = append(sessions, utils.ToSessionDTO(&histories[i], currentSessionID))
+	}
+	return sessions, nil
+}
+
+// 특정 세션 폐기
+func (s *AuthService) RevokeSession(userID, sessionID string) error {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeUserLoginHistory(id, sessionID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	s.sessions.DisconnectSession(sessionID)
+	return nil
+}
+
+// 재사용이 감지된 토큰 패밀리 폐기
+func (s *AuthService) revokeReusedFamily(familyID string) error {
+	log.Printf("Refresh token reuse detected for family %s", familyID)
+	if err := s.repo.RevokeLoginHistoryFamily(familyID); err != nil {
+		return err
+	}
+	s.sessions.DisconnectSession(familyID)
+	return ErrRefreshTokenReused
+}
+
+// access/refresh 토큰 쌍 생성
+func (s *AuthService) generateTokenPair(user *models.User, familyID string) (string, string, error) {
+	accessToken, err := s.generateToken(user.ID.Hex(), familyID, user.Role, tokenTypeAccess, s.accessSecret, accessTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	refreshToken, err := s.generateToken(user.ID.Hex(), familyID, user.Role, tokenTypeRefresh, s.refreshSecret, refreshTokenTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return accessToken, refreshToken, nil
 }
 
 // 토큰 생성
-func (s *AuthService) generateToken(userID string, secret string, duration time.Duration) (string, error) {
+func (s *AuthService) generateToken(userID, familyID, role, tokenType, secret string, duration time.Duration) (string, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(duration).Unix(),
+		"fid":     familyID,
+		"role":    role,
+		"typ":     tokenType,
+		"jti":     jti,
+		"iat":     now.Unix(),
+		"exp":     now.Add(duration).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
 
+// 토큰 검증 및 클레임 추출
+func (s *AuthService) parseToken(tokenString, secret, tokenType string) (*TokenClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	}, jwt.WithTimeFunc(s.now))
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+
+	userID, _ := claims["user_id"].(string)
+	familyID, _ := claims["fid"].(string)
+	typ, _ := claims["typ"].(string)
+	role, _ := claims["role"].(string)
+	if userID == "" || familyID == "" || typ != tokenType {
+		return nil, errors.New("invalid token claims")
+	}
+
+	return &TokenClaims{UserID: userID, FamilyID: familyID, Type: typ, Role: role}, nil
+}
+
 // 회원가입 로직
 func (s *AuthService) Register(email, password, name string) error {
 	// 이메일 중복 확인
@@ -149,7 +427,7 @@ func (s *AuthService) Register(email, password, name string) error {
 		Email:           email,
 		Password:        string(hashedPassword),
 		Name:            name,
-		Role:            "user",
+		Role:            models.RoleUser,
 		IsEmailVerified: false,
 	}
 	if err := s.repo.CreateUser(user); err != nil {
@@ -201,9 +479,70 @@ func (s *AuthService) VerifyEmail(token string) error {
 	return nil
 }
 
+// 비밀번호 재설정 요청
+// 존재하지 않는 이메일이어도 에러를 반환하지 않아 가입 여부가 노출되지 않도록 한다.
+func (s *AuthService) RequestPasswordReset(email string) error {
+	user, err := s.repo.FindByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	// 이전에 발급된 토큰은 무효화
+	if err := s.repo.InvalidatePasswordResets(user.ID); err != nil {
+		return err
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+
+	reset := &models.PasswordReset{
+		UserID: user.ID,
+		Token:  token,
+	}
+	if err := s.repo.AddPasswordReset(reset); err != nil {
+		return fmt.Errorf("failed to save reset token: %v", err)
+	}
+
+	return s.emailService.SendPasswordResetEmailAsync(user.Email, token)
+}
+
+// 비밀번호 재설정
+// 재설정이 완료되면 기존의 모든 세션을 폐기한다.
+func (s *AuthService) ResetPassword(token, newPassword string) error {
+	if newPassword == "" {
+		return errors.New("password is required")
+	}
+
+	reset, err := s.repo.ConsumePasswordReset(token)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(reset.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	if err := s.repo.RevokeAllLoginHistory(reset.UserID); err != nil {
+		return err
+	}
+	s.sessions.DisconnectUser(reset.UserID.Hex())
+	return nil
+}
+
 // 이메일 인증 토큰 생성
 func (s *AuthService) generateVerificationToken() (string, error) {
-	bytes := make([]byte, 16)
+	return randomHex(16)
+}
+
+// n 바이트 난수를 hex 문자열로 반환
+func randomHex(n int) (string, error) {
+	bytes := make([]byte, n)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
 	}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"testing"
+)
+
+// 인증 이메일 재발송 여부를 기록하는 저장소
+type verificationRecordingStore struct {
+	*fakeUserStore
+	verifications []*models.EmailVerification
+}
+
+func (f *verificationRecordingStore) AddEmailVerification(verification *models.EmailVerification) error {
+	f.verifications = append(f.verifications, verification)
+	return nil
+}
+
+func TestLoginDisabledUnverifiedAccount(t *testing.T) {
+	_, store, user, _ := newTwoFactorTestService(t)
+	user.IsEmailVerified = false
+	user.IsDisabled = true
+
+	recording := &verificationRecordingStore{fakeUserStore: store}
+	service := NewAuthService(recording, nil, nil, "access-secret", "refresh-secret")
+
+	if _, err := service.Login(user.Email, testPassword, "test", "127.0.0.1"); !errors.Is(err, ErrAccountDisabled) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrAccountDisabled)
+	}
+	if len(recording.verifications) != 0 {
+		t.Errorf("Login() resent %d verification emails to a disabled account", len(recording.verifications))
+	}
+}
+
+func TestLoginWrongPasswordDoesNotResendVerification(t *testing.T) {
+	_, store, user, _ := newTwoFactorTestService(t)
+	user.IsEmailVerified = false
+
+	recording := &verificationRecordingStore{fakeUserStore: store}
+	service := NewAuthService(recording, nil, nil, "access-secret", "refresh-secret")
+
+	if _, err := service.Login(user.Email, "wrong-password", "test", "127.0.0.1"); err == nil {
+		t.Fatal("Login() error = nil, want error")
+	}
+	if len(recording.verifications) != 0 {
+		t.Errorf("Login() resent %d verification emails without a valid password", len(recording.verifications))
+	}
+}
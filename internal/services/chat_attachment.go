@@ -0,0 +1,463 @@
+package services
+
+import (
+	"bufio"
+	"chat-go-api/internal/imaging"
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/storage"
+	"chat-go-api/internal/utils"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxAttachmentsPerMessage = 10
+	maxAttachmentNameLength  = 255
+	sniffLength              = 512 // http.DetectContentType이 사용하는 최대 길이
+	unclaimedCleanupBatch    = 100
+)
+
+var (
+	ErrAttachmentNotFound    = errors.New("attachment not found")
+	ErrAttachmentTooLarge    = errors.New("attachment is too large")
+	ErrInvalidAttachment     = errors.New("invalid attachment")
+	ErrInvalidDownloadLink   = errors.New("download link is invalid or expired")
+	ErrAttachmentProcessing  = errors.New("attachment is still being processed")
+	ErrAttachmentUnavailable = errors.New("attachment could not be processed")
+	ErrUnsupportedImage      = errors.New("image format is not supported")
+)
+
+// UploadAttachment 첨부파일 업로드 (채팅방 멤버만)
+// 내용으로 MIME 타입을 판별하고 저장하면서 크기와 SHA-256 체크섬을 계산한다.
+// 업로드한 첨부파일은 메시지 전송 시 attachment_ids로 참조한다.
+func (s *ChatService) UploadAttachment(roomID, userID, fileName string, file io.Reader, size int64) (*models.AttachmentDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	if size <= 0 {
+		return nil, ErrInvalidAttachment
+	}
+	if s.options.MaxUploadSize > 0 && size > s.options.MaxUploadSize {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	reader := bufio.NewReaderSize(file, sniffLength)
+	head, err := reader.Peek(sniffLength)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	contentType := http.DetectContentType(head)
+	if imaging.IsUnsupportedImage(contentType, head) {
+		return nil, ErrUnsupportedImage
+	}
+
+	uploader, _ := primitive.ObjectIDFromHex(userID)
+	attachment := &models.Attachment{
+		ID:          primitive.NewObjectID(),
+		RoomID:      room.ID,
+		UploaderID:  uploader,
+		FileName:    sanitizeFileName(fileName),
+		ContentType: contentType,
+		CreatedAt:   time.Now().Unix(),
+	}
+	if imaging.IsSupported(contentType) {
+		// 이미지는 메타데이터 제거와 썸네일 생성이 끝난 뒤 내려받을 수 있음
+		attachment.Status = models.AttachmentStatusProcessing
+	}
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s", room.ID.Hex(), attachment.ID.Hex())
+
+	hasher := sha256.New()
+	counter := &countingReader{reader: io.TeeReader(io.LimitReader(reader, size), hasher)}
+	if err := s.blobStore.Put(context.TODO(), attachment.StorageKey, counter, size, contentType); err != nil {
+		return nil, err
+	}
+	if counter.n != size {
+		s.deleteBlob(attachment.StorageKey)
+		return nil, ErrInvalidAttachment
+	}
+	attachment.Size = counter.n
+	attachment.SHA256 = hex.EncodeToString(hasher.Sum(nil))
+
+	if err := s.attachmentRepo.CreateAttachment(attachment); err != nil {
+		s.deleteBlob(attachment.StorageKey)
+		return nil, err
+	}
+	if attachment.Status == models.AttachmentStatusProcessing {
+		if err := s.imageService.ProcessImageAsync(attachment.ID); err != nil {
+			if deleteErr := s.attachmentRepo.DeleteAttachment(attachment.ID); deleteErr != nil {
+				log.Printf("Failed to delete attachment %s: %v", attachment.ID.Hex(), deleteErr)
+			}
+			s.deleteBlob(attachment.StorageKey)
+			return nil, err
+		}
+	}
+	return utils.ToAttachmentDTO(attachment), nil
+}
+
+// MaxUploadSize 첨부파일 최대 크기(바이트), 0이면 제한 없음
+func (s *ChatService) MaxUploadSize() int64 {
+	return s.options.MaxUploadSize
+}
+
+// AttachmentContent 다운로드할 첨부파일 원본 또는 썸네일
+// 호출한 쪽에서 Body를 닫아야 한다.
+type AttachmentContent struct {
+	ID          primitive.ObjectID
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.ReadCloser
+}
+
+// GetAttachmentURL 첨부파일의 서명된 다운로드 URL 발급 (채팅방 멤버만)
+// variant를 지정하면 원본 대신 해당 썸네일의 URL을 발급한다.
+func (s *ChatService) GetAttachmentURL(roomID, userID, attachmentID, variant string) (*models.AttachmentURLDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	attachment, err := s.getAttachment(attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.RoomID != room.ID {
+		return nil, ErrAttachmentNotFound
+	}
+	// 아직 메시지로 보내지 않은 첨부파일은 업로더만 볼 수 있음
+	if attachment.MessageID == nil && attachment.UploaderID.Hex() != userID {
+		return nil, ErrAttachmentNotFound
+	}
+	if _, err := attachmentObject(attachment, variant); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.options.DownloadURLTTL).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt, 10))
+	if variant != "" {
+		query.Set("variant", variant)
+	}
+	query.Set("signature", s.signDownload(attachment.ID.Hex(), variant, query.Get("expires")))
+	return &models.AttachmentURLDTO{
+		URL:       fmt.Sprintf("/attachments/%s/download?%s", attachment.ID.Hex(), query.Encode()),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// OpenAttachment 서명된 다운로드 URL 검증 후 첨부파일 원본 또는 썸네일 열기
+func (s *ChatService) OpenAttachment(attachmentID, variant, expires, signature string) (*AttachmentContent, error) {
+	if err := s.verifyDownload(attachmentID, variant, expires, signature); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.getAttachment(attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	content, err := attachmentObject(attachment, variant)
+	if err != nil {
+		return nil, err
+	}
+	body, err := s.blobStore.Get(context.TODO(), content.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &AttachmentContent{
+		ID:          attachment.ID,
+		FileName:    content.FileName,
+		ContentType: content.ContentType,
+		Size:        content.Size,
+		Body:        body,
+	}, nil
+}
+
+// 다운로드할 객체 (원본 또는 썸네일)
+type attachmentBlob struct {
+	StorageKey  string
+	FileName    string
+	ContentType string
+	Size        int64
+}
+
+func attachmentObject(attachment *models.Attachment, variant string) (*attachmentBlob, error) {
+	if variant == "" {
+		switch attachment.Status {
+		case models.AttachmentStatusProcessing:
+			return nil, ErrAttachmentProcessing
+		case models.AttachmentStatusFailed:
+			return nil, ErrAttachmentUnavailable
+		}
+		return &attachmentBlob{
+			StorageKey:  attachment.StorageKey,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+		}, nil
+	}
+
+	thumbnail := attachment.Variant(variant)
+	if thumbnail == nil {
+		if attachment.Status == models.AttachmentStatusProcessing {
+			return nil, ErrAttachmentProcessing
+		}
+		return nil, ErrAttachmentNotFound
+	}
+	return &attachmentBlob{
+		StorageKey:  thumbnail.StorageKey,
+		FileName:    variantFileName(attachment.FileName, variant, thumbnail.ContentType),
+		ContentType: thumbnail.ContentType,
+		Size:        thumbnail.Size,
+	}, nil
+}
+
+// 썸네일 파일 이름 (예: photo.jpeg -> photo_small.jpg)
+func variantFileName(fileName, variant, contentType string) string {
+	ext := ".jpg"
+	if contentType == "image/png" {
+		ext = ".png"
+	}
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "_" + variant + ext
+}
+
+// 메시지가 참조하는 첨부파일을 메시지에 연결하고 정보를 채움
+// 업로더 본인이 같은 채팅방에 올린, 아직 다른 메시지에 쓰이지 않은 첨부파일만 허용한다.
+func (s *ChatService) claimAttachments(msg *models.Message) error {
+	ids := make([]primitive.ObjectID, 0, len(msg.Attachments))
+	for _, attachment := range msg.Attachments {
+		ids = append(ids, attachment.ID)
+	}
+	ids = uniqueObjectIDs(ids)
+	if len(ids) > maxAttachmentsPerMessage {
+		return ErrInvalidAttachment
+	}
+
+	if msg.ID.IsZero() {
+		msg.ID = primitive.NewObjectID()
+	}
+	claimed, err := s.attachmentRepo.ClaimAttachments(ids, msg.SenderID, msg.RoomID, msg.ID)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrInvalidAttachment
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByIDs(ids)
+	if err != nil {
+		return err
+	}
+	msg.Attachments = make([]models.MessageAttachment, 0, len(ids))
+	for _, id := range ids {
+		msg.Attachments = append(msg.Attachments, attachments[id].ToMessageAttachment())
+	}
+	return nil
+}
+
+// 메시지 저장 직후 그 사이 처리가 끝난 이미지의 정보를 메시지에 반영
+// 처리 완료 알림이 메시지 저장보다 먼저 도착하면 메시지를 찾지 못하므로 저장 후 한 번 더 확인한다.
+func (s *ChatService) syncProcessingAttachments(msg *models.Message) {
+	var ids []primitive.ObjectID
+	for _, attachment := range msg.Attachments {
+		if attachment.Status == models.AttachmentStatusProcessing {
+			ids = append(ids, attachment.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	attachments, err := s.attachmentRepo.GetAttachmentsByIDs(ids)
+	if err != nil {
+		log.Printf("Failed to load attachments of message %s: %v", msg.ID.Hex(), err)
+		return
+	}
+	for i, embedded := range msg.Attachments {
+		attachment, ok := attachments[embedded.ID]
+		if !ok || attachment.Status == models.AttachmentStatusProcessing {
+			continue
+		}
+		msg.Attachments[i] = attachment.ToMessageAttachment()
+		if _, err := s.messageRepo.UpdateMessageAttachment(msg.ID, msg.Attachments[i]); err != nil {
+			log.Printf("Failed to update attachment %s of message %s: %v", attachment.ID.Hex(), msg.ID.Hex(), err)
+		}
+	}
+}
+
+// 이미지 처리가 끝난 첨부파일을 메시지에 반영하고 message.updated 이벤트 전송
+func (s *ChatService) attachmentProcessed(attachment *models.Attachment) {
+	if attachment.MessageID == nil {
+		return // 메시지에 연결될 때 처리 결과가 함께 저장됨
+	}
+	message, err := s.messageRepo.UpdateMessageAttachment(*attachment.MessageID, attachment.ToMessageAttachment())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return // 아직 저장 전이거나 삭제된 메시지
+	}
+	if err != nil {
+		log.Printf("Failed to update attachment %s of message %s: %v", attachment.ID.Hex(), attachment.MessageID.Hex(), err)
+		return
+	}
+
+	messageDTO, err := utils.ToMessageDTO(message, s.GetUserName)
+	if err != nil {
+		log.Printf("Failed to convert message %s: %v", message.ID.Hex(), err)
+		return
+	}
+	s.publishEvent(message.RoomID, models.EventMessageUpdated, messageDTO)
+}
+
+// 삭제된 메시지의 첨부파일 정리 (원본까지 삭제)
+func (s *ChatService) purgeMessageAttachments(messageID primitive.ObjectID) {
+	attachments, err := s.attachmentRepo.DeleteAttachmentsByMessageID(messageID)
+	if err != nil {
+		log.Printf("Failed to delete attachments of message %s: %v", messageID.Hex(), err)
+		return
+	}
+	for _, attachment := range attachments {
+		s.deleteAttachmentBlobs(attachment)
+	}
+}
+
+// 삭제된 채팅방의 첨부파일 정리 (원본까지 삭제)
+func (s *ChatService) purgeRoomAttachments(roomID primitive.ObjectID) error {
+	attachments, err := s.attachmentRepo.DeleteAttachmentsByRoomID(roomID)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		s.deleteAttachmentBlobs(attachment)
+	}
+	return nil
+}
+
+// StartAttachmentCleanup 메시지에 연결되지 않은 채 보관 기간이 지난 업로드를 주기적으로 삭제
+func (s *ChatService) StartAttachmentCleanup(interval time.Duration) {
+	if s.options.UnclaimedAttachmentTTL <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.cleanupUnclaimedAttachments(); err != nil {
+				log.Printf("Failed to clean up unclaimed attachments: %v", err)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *ChatService) cleanupUnclaimedAttachments() error {
+	createdBefore := time.Now().Add(-s.options.UnclaimedAttachmentTTL).Unix()
+	for {
+		attachments, err := s.attachmentRepo.DeleteUnclaimedAttachments(createdBefore, unclaimedCleanupBatch)
+		for _, attachment := range attachments {
+			s.deleteAttachmentBlobs(attachment)
+		}
+		if err != nil {
+			return err
+		}
+		if len(attachments) < unclaimedCleanupBatch {
+			return nil
+		}
+	}
+}
+
+// 첨부파일 원본과 썸네일 삭제
+func (s *ChatService) deleteAttachmentBlobs(attachment *models.Attachment) {
+	s.deleteBlob(attachment.StorageKey)
+	for _, variant := range attachment.Variants {
+		s.deleteBlob(variant.StorageKey)
+	}
+}
+
+func (s *ChatService) deleteBlob(key string) {
+	if err := s.blobStore.Delete(context.TODO(), key); err != nil {
+		log.Printf("Failed to delete blob %s: %v", key, err)
+	}
+}
+
+func (s *ChatService) getAttachment(attachmentID string) (*models.Attachment, error) {
+	id, err := primitive.ObjectIDFromHex(attachmentID)
+	if err != nil {
+		return nil, ErrAttachmentNotFound
+	}
+	attachment, err := s.attachmentRepo.GetAttachmentByID(id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrAttachmentNotFound
+	}
+	return attachment, err
+}
+
+// 다운로드 URL 서명 (첨부파일 ID, 썸네일 이름, 만료 시각에 대한 HMAC-SHA256)
+func (s *ChatService) signDownload(attachmentID, variant, expires string) string {
+	mac := hmac.New(sha256.New, s.options.DownloadSigningKey)
+	mac.Write([]byte(attachmentID + ":" + variant + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// 다운로드 URL의 만료 시각과 서명 검증
+func (s *ChatService) verifyDownload(attachmentID, variant, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return ErrInvalidDownloadLink
+	}
+	expected := s.signDownload(attachmentID, variant, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidDownloadLink
+	}
+	return nil
+}
+
+// 경로와 제어 문자를 제거한 파일 이름
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if runes := []rune(name); len(runes) > maxAttachmentNameLength {
+		name = string(runes[:maxAttachmentNameLength])
+	}
+	return name
+}
+
+// 읽은 바이트 수를 세는 Reader
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyDownload(t *testing.T) {
+	service := &ChatService{options: ChatOptions{DownloadSigningKey: []byte("test-signing-key-0123456789abcdef")}}
+	other := &ChatService{options: ChatOptions{DownloadSigningKey: []byte("other-signing-key-0123456789abcde")}}
+
+	const attachmentID = "65f1c0ffee0000000000abcd"
+	valid := strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10)
+	expired := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	signature := service.signDownload(attachmentID, "", valid)
+	tampered := []byte(signature)
+	tampered[0] ^= 1
+
+	tests := []struct {
+		name         string
+		attachmentID string
+		variant      string
+		expires      string
+		signature    string
+		wantErr      bool
+	}{
+		{"valid", attachmentID, "", valid, signature, false},
+		{"valid variant", attachmentID, "small", valid, service.signDownload(attachmentID, "small", valid), false},
+		{"expired", attachmentID, "", expired, service.signDownload(attachmentID, "", expired), true},
+		{"extended expiry", attachmentID, "", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10), signature, true},
+		{"other attachment", "65f1c0ffee0000000000dcba", "", valid, signature, true},
+		{"added variant", attachmentID, "large", valid, signature, true},
+		{"tampered signature", attachmentID, "", valid, string(tampered), true},
+		{"other key", attachmentID, "", valid, other.signDownload(attachmentID, "", valid), true},
+		{"malformed expiry", attachmentID, "", "soon", signature, true},
+		{"missing signature", attachmentID, "", valid, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.verifyDownload(tt.attachmentID, tt.variant, tt.expires, tt.signature)
+			if tt.wantErr && !errors.Is(err, ErrInvalidDownloadLink) {
+				t.Fatalf("got %v, want ErrInvalidDownloadLink", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("got %v, want nil", err)
+			}
+		})
+	}
+}
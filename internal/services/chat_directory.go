@@ -0,0 +1,196 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/utils"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAlreadyRoomMember   = errors.New("already a member of this chat room")
+	ErrJoinRequestNotFound = errors.New("join request not found")
+	ErrJoinRequestClosed   = errors.New("chat room no longer accepts join requests")
+)
+
+// JoinResult 채팅방 참여 결과
+// 승인이 필요한 채팅방이면 Joined는 false이고 Request가 채워진다.
+type JoinResult struct {
+	Joined  bool                   `json:"joined"`
+	Request *models.JoinRequestDTO `json:"request,omitempty"`
+}
+
+// SearchPublicRooms 공개 채팅방 디렉터리 검색
+func (s *ChatService) SearchPublicRooms(userID, query string, limit, page int64) ([]*models.PublicRoomDTO, int64, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rooms, total, err := s.chatRepo.SearchDiscoverableRooms(query, limit, page)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	roomDTOs := []*models.PublicRoomDTO{}
+	for i := range rooms {
+		roomDTOs = append(roomDTOs, utils.ToPublicRoomDTO(&rooms[i], uid))
+	}
+	return roomDTOs, total, nil
+}
+
+// JoinRoom 공개 채팅방 참여 또는 승인제 채팅방 참여 요청
+// 비공개 채팅방은 존재 여부를 드러내지 않도록 찾을 수 없음으로 처리한다.
+func (s *ChatService) JoinRoom(roomID, userID string) (*JoinResult, error) {
+	room, uid, err := s.getDiscoverableRoom(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if containsObjectID(room.Members, uid) {
+		return nil, ErrAlreadyRoomMember
+	}
+
+	if room.Visibility == models.RoomVisibilityPublic {
+		if err := s.chatRepo.AddMembers(room.ID, []primitive.ObjectID{uid}); err != nil {
+			return nil, err
+		}
+		s.publishMemberEvent(room.ID, models.EventMemberJoined, uid, uid)
+		return &JoinResult{Joined: true}, nil
+	}
+
+	request, err := s.chatRepo.CreateJoinRequest(&models.JoinRequest{
+		RoomID:    room.ID,
+		UserID:    uid,
+		Status:    models.JoinRequestPending,
+		CreatedAt: time.Now().Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requestDTO := s.toJoinRequestDTO(request)
+	s.publishEventToManagers(room, models.EventJoinRequested, requestDTO)
+	return &JoinResult{Joined: false, Request: requestDTO}, nil
+}
+
+// GetJoinRequests 대기 중인 참여 요청 목록 (방장/관리자만)
+func (s *ChatService) GetJoinRequests(roomID, actorID string) ([]*models.JoinRequestDTO, error) {
+	room, _, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	actor, _ := primitive.ObjectIDFromHex(actorID)
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+
+	requests, err := s.chatRepo.GetPendingJoinRequests(room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	requestDTOs := []*models.JoinRequestDTO{}
+	for i := range requests {
+		requestDTOs = append(requestDTOs, s.toJoinRequestDTO(&requests[i]))
+	}
+	return requestDTOs, nil
+}
+
+// DecideJoinRequest 참여 요청 승인/거절 (방장/관리자만)
+func (s *ChatService) DecideJoinRequest(roomID, actorID, requestID string, approve bool) (*models.JoinRequestDTO, error) {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+	// 비공개로 전환된 채팅방은 남아 있는 요청을 승인할 수 없음
+	if approve && !room.IsDiscoverable() {
+		return nil, ErrJoinRequestClosed
+	}
+
+	requestObjectID, err := primitive.ObjectIDFromHex(requestID)
+	if err != nil {
+		return nil, ErrJoinRequestNotFound
+	}
+
+	status := models.JoinRequestDenied
+	if approve {
+		status = models.JoinRequestApproved
+	}
+
+	request, err := s.chatRepo.DecideJoinRequest(requestObjectID, room.ID, status, actor)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrJoinRequestNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if approve {
+		if err := s.chatRepo.AddMembers(room.ID, []primitive.ObjectID{request.UserID}); err != nil {
+			return nil, err
+		}
+		s.publishMemberEvent(room.ID, models.EventMemberAdded, request.UserID, actor)
+	}
+	return s.toJoinRequestDTO(request), nil
+}
+
+// 방장과 관리자에게만 이벤트 전송 (참여 요청처럼 일반 멤버에게 보이면 안 되는 이벤트용)
+func (s *ChatService) publishEventToManagers(room *models.ChatRoom, eventType string, data interface{}) {
+	event := &models.RoomEvent{
+		Type:      eventType,
+		RoomID:    room.ID.Hex(),
+		Data:      data,
+		CreatedAt: time.Now().Unix(),
+	}
+	for _, managerID := range uniqueObjectIDs(append([]primitive.ObjectID{room.OwnerID}, room.Admins...)) {
+		if managerID.IsZero() {
+			continue
+		}
+		s.manager.SendEventToUser(managerID.Hex(), event)
+	}
+}
+
+// 디렉터리에 노출되는 채팅방 조회 (비공개 채팅방은 멤버가 아니면 찾을 수 없음)
+func (s *ChatService) getDiscoverableRoom(roomID, userID string) (*models.ChatRoom, primitive.ObjectID, error) {
+	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
+	if err != nil {
+		return nil, primitive.NilObjectID, ErrRoomNotFound
+	}
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+
+	room, err := s.chatRepo.GetChatRoomByID(roomObjectID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, primitive.NilObjectID, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	if !room.IsDiscoverable() && !containsObjectID(room.Members, uid) {
+		return nil, primitive.NilObjectID, ErrRoomNotFound
+	}
+	return room, uid, nil
+}
+
+func (s *ChatService) toJoinRequestDTO(request *models.JoinRequest) *models.JoinRequestDTO {
+	return &models.JoinRequestDTO{
+		ID:        request.ID,
+		RoomID:    request.RoomID,
+		UserID:    request.UserID,
+		UserName:  s.userName(request.UserID),
+		Status:    request.Status,
+		CreatedAt: request.CreatedAt,
+		DecidedAt: request.DecidedAt,
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxInviteTTL = 30 * 24 * time.Hour
+
+var (
+	ErrInviteNotFound = errors.New("invite not found")
+	ErrInviteInvalid  = errors.New("invite has expired, been revoked or reached its usage limit")
+	ErrInvalidInvite  = errors.New("invalid invite options")
+)
+
+// InviteOptions 초대 코드 생성 옵션
+type InviteOptions struct {
+	Role      string        // 비어 있으면 member
+	MaxUses   int           // 0이면 무제한
+	ExpiresIn time.Duration // 0이면 만료 없음
+}
+
+// CreateInvite 채팅방 초대 코드 생성 (방장/관리자, admin 역할 부여는 방장만)
+func (s *ChatService) CreateInvite(roomID, actorID string, opts InviteOptions) (*models.RoomInviteDTO, error) {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+
+	if opts.Role == "" {
+		opts.Role = models.RoomRoleMember
+	}
+	if opts.Role != models.RoomRoleMember && opts.Role != models.RoomRoleAdmin {
+		return nil, ErrInvalidRoomRole
+	}
+	if opts.Role == models.RoomRoleAdmin && room.OwnerID != actor {
+		return nil, ErrRoomPermissionDenied
+	}
+	if opts.MaxUses < 0 || opts.ExpiresIn < 0 || opts.ExpiresIn > maxInviteTTL {
+		return nil, ErrInvalidInvite
+	}
+
+	code, err := randomHex(6)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invite := &models.RoomInvite{
+		Code:      code,
+		RoomID:    room.ID,
+		CreatedBy: actor,
+		Role:      opts.Role,
+		MaxUses:   opts.MaxUses,
+		CreatedAt: now.Unix(),
+	}
+	if opts.ExpiresIn > 0 {
+		invite.ExpiresAt = now.Add(opts.ExpiresIn).Unix()
+	}
+
+	if err := s.chatRepo.CreateInvite(invite); err != nil {
+		return nil, err
+	}
+	return toRoomInviteDTO(invite), nil
+}
+
+// GetInvites 채팅방의 유효한 초대 코드 목록 (방장/관리자)
+func (s *ChatService) GetInvites(roomID, actorID string) ([]*models.RoomInviteDTO, error) {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+
+	invites, err := s.chatRepo.GetInvitesByRoomID(room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	inviteDTOs := []*models.RoomInviteDTO{}
+	for i := range invites {
+		inviteDTOs = append(inviteDTOs, toRoomInviteDTO(&invites[i]))
+	}
+	return inviteDTOs, nil
+}
+
+// RevokeInvite 초대 코드 폐기 (방장/관리자)
+func (s *ChatService) RevokeInvite(roomID, actorID, code string) error {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if !canManageMembers(room, actor) {
+		return ErrRoomPermissionDenied
+	}
+
+	revoked, err := s.chatRepo.RevokeInvite(room.ID, code)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInviteNotFound
+	}
+	return nil
+}
+
+// AcceptInvite 초대 코드로 채팅방 참여
+// 이미 멤버인 경우 사용 횟수를 소모하지 않는다.
+func (s *ChatService) AcceptInvite(code, userID string) (*models.ChatRoomDTO, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invite, err := s.chatRepo.GetInviteByCode(code)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.chatRepo.GetChatRoomByID(invite.RoomID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInviteNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	if containsObjectID(room.Members, uid) {
+		return nil, ErrAlreadyRoomMember
+	}
+
+	invite, err = s.chatRepo.RedeemInvite(code)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInviteInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.chatRepo.AddMembers(room.ID, []primitive.ObjectID{uid}); err != nil {
+		return nil, err
+	}
+	if invite.Role == models.RoomRoleAdmin {
+		if _, err := s.chatRepo.SetAdmin(room.ID, uid, true); err != nil {
+			return nil, err
+		}
+	}
+	s.publishMemberEvent(room.ID, models.EventMemberJoined, uid, invite.CreatedBy)
+
+	updated, err := s.chatRepo.GetChatRoomByID(room.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.ToChatRoomDTO(updated, userID)
+}
+
+func toRoomInviteDTO(invite *models.RoomInvite) *models.RoomInviteDTO {
+	return &models.RoomInviteDTO{
+		Code:      invite.Code,
+		RoomID:    invite.RoomID,
+		CreatedBy: invite.CreatedBy,
+		Role:      invite.Role,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		ExpiresAt: invite.ExpiresAt,
+		RevokedAt: invite.RevokedAt,
+		CreatedAt: invite.CreatedAt,
+	}
+}
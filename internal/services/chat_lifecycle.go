@@ -0,0 +1,159 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrRoomArchived = errors.New("chat room is archived")
+
+const (
+	maxRoomPurgeAttempts = 5                // 채팅방 데이터 정리 최대 시도 횟수 (넘기면 다음 서버 시작 시 재시도)
+	roomPurgeRetryDelay  = 10 * time.Second // 첫 재시도 대기 시간 (시도마다 두 배)
+)
+
+// ArchiveRoom 채팅방 보관 (방장/관리자만)
+// 보관된 채팅방은 읽기 전용이 되며 기본 채팅방 목록에서 숨겨진다.
+func (s *ChatService) ArchiveRoom(roomID, actorID string) (*models.ChatRoomDTO, error) {
+	return s.setArchived(roomID, actorID, true)
+}
+
+// UnarchiveRoom 채팅방 보관 해제 (방장/관리자만)
+func (s *ChatService) UnarchiveRoom(roomID, actorID string) (*models.ChatRoomDTO, error) {
+	return s.setArchived(roomID, actorID, false)
+}
+
+func (s *ChatService) setArchived(roomID, actorID string, archive bool) (*models.ChatRoomDTO, error) {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+	if room.IsArchived() == archive {
+		return s.ToChatRoomDTO(room, actorID)
+	}
+
+	var archivedAt int64
+	eventType, verb := models.EventRoomUnarchived, "unarchived"
+	if archive {
+		archivedAt = time.Now().Unix()
+		eventType, verb = models.EventRoomArchived, "archived"
+	}
+
+	updated, err := s.chatRepo.SetArchived(room.ID, archivedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.postSystemMessage(room.ID, actor, fmt.Sprintf("%s %s the room", s.userName(actor), verb)); err != nil {
+		log.Printf("Failed to post system message: %v", err)
+	}
+	s.publishEvent(room.ID, eventType, map[string]interface{}{
+		"actor_id":    actor,
+		"archived_at": archivedAt,
+	})
+	return s.ToChatRoomDTO(updated, actorID)
+}
+
+// DeleteRoom 채팅방 영구 삭제 (방장만)
+// 접속 중인 클라이언트에 room.closed 이벤트를 보낸 뒤 연결을 끊고,
+// 메시지 등 관련 데이터는 백그라운드에서 삭제한다.
+func (s *ChatService) DeleteRoom(roomID, actorID string) error {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID != actor {
+		return ErrRoomPermissionDenied
+	}
+
+	// 정리 도중 서버가 멈춰도 다음 시작 시 이어서 정리할 수 있도록 채팅방보다 먼저 기록
+	if err := s.chatRepo.AddRoomPurge(&models.RoomPurge{RoomID: room.ID, RequestedAt: time.Now().Unix()}); err != nil {
+		return err
+	}
+	deleted, err := s.chatRepo.DeleteChatRoom(room.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrRoomNotFound
+	}
+
+	s.publishEvent(room.ID, models.EventRoomClosed, map[string]interface{}{
+		"actor_id": actor,
+	})
+	s.manager.CloseRoom(room.ID.Hex())
+
+	go s.runRoomPurge(room.ID, 0)
+	return nil
+}
+
+// ResumeRoomPurges 이전 실행에서 끝나지 않은 채팅방 데이터 정리를 다시 시작
+func (s *ChatService) ResumeRoomPurges() error {
+	purges, err := s.chatRepo.GetRoomPurges()
+	if err != nil {
+		return err
+	}
+	for _, purge := range purges {
+		go s.runRoomPurge(purge.RoomID, 0)
+	}
+	return nil
+}
+
+// 채팅방 데이터 정리 실행 (실패하면 간격을 늘려가며 재시도)
+// 재시도 횟수를 넘기면 기록을 남겨 두고 다음 서버 시작 시 다시 시도한다.
+func (s *ChatService) runRoomPurge(roomID primitive.ObjectID, attempt int) {
+	err := s.purgeRoomData(roomID)
+	if err == nil {
+		if err := s.chatRepo.DeleteRoomPurge(roomID); err != nil {
+			log.Printf("Failed to clear purge marker of room %s: %v", roomID.Hex(), err)
+		}
+		return
+	}
+
+	attempt++
+	if attempt >= maxRoomPurgeAttempts {
+		log.Printf("Failed to purge room %s after %d attempts, will retry on next start: %v", roomID.Hex(), attempt, err)
+		return
+	}
+	delay := roomPurgeRetryDelay << (attempt - 1)
+	log.Printf("Failed to purge room %s, retrying in %s: %v", roomID.Hex(), delay, err)
+	time.AfterFunc(delay, func() {
+		s.runRoomPurge(roomID, attempt)
+	})
+}
+
+// 삭제된 채팅방의 메시지, 첨부파일, 참여 요청, 초대 코드, 검색 색인 정리
+// 각 단계는 여러 번 실행해도 안전하다.
+func (s *ChatService) purgeRoomData(roomID primitive.ObjectID) error {
+	// 삭제 기록만 남고 채팅방 삭제가 실패한 경우에는 데이터를 지우지 않는다.
+	if _, err := s.chatRepo.GetChatRoomByID(roomID); err == nil {
+		return nil
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+
+	count, err := s.messageRepo.DeleteMessagesByRoomID(roomID)
+	if err != nil {
+		return fmt.Errorf("delete messages: %w", err)
+	}
+	if err := s.chatRepo.DeleteRoomRelatedData(roomID); err != nil {
+		return fmt.Errorf("delete related data: %w", err)
+	}
+	if err := s.purgeRoomAttachments(roomID); err != nil {
+		return fmt.Errorf("delete attachments: %w", err)
+	}
+	if err := s.searcher.RemoveRoom(roomID); err != nil {
+		return fmt.Errorf("remove from search index: %w", err)
+	}
+	log.Printf("Purged room %s (%d messages)", roomID.Hex(), count)
+	return nil
+}
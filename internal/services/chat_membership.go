@@ -0,0 +1,298 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrRoomPermissionDenied = errors.New("insufficient permissions in this chat room")
+	ErrNotRoomMember        = errors.New("user is not a member of this chat room")
+	ErrOwnerCannotLeave     = errors.New("room owner must transfer ownership before leaving")
+	ErrInvalidRoomRole      = errors.New("invalid room role")
+	ErrDirectRoomMembership = errors.New("membership of a direct message room cannot be changed")
+)
+
+// RoomRole 채팅방 내 유저의 역할 (멤버가 아니면 빈 문자열)
+func RoomRole(room *models.ChatRoom, userID primitive.ObjectID) string {
+	switch {
+	case room.OwnerID == userID:
+		return models.RoomRoleOwner
+	case containsObjectID(room.Admins, userID):
+		return models.RoomRoleAdmin
+	case containsObjectID(room.Members, userID):
+		return models.RoomRoleMember
+	default:
+		return ""
+	}
+}
+
+// canManageMembers 방장 또는 관리자만 멤버를 관리할 수 있음
+func canManageMembers(room *models.ChatRoom, userID primitive.ObjectID) bool {
+	role := RoomRole(room, userID)
+	return role == models.RoomRoleOwner || role == models.RoomRoleAdmin
+}
+
+// AddMembers 채팅방에 멤버 추가
+func (s *ChatService) AddMembers(roomID, actorID string, userIDs []primitive.ObjectID) error {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+	if !canManageMembers(room, actor) {
+		return ErrRoomPermissionDenied
+	}
+
+	// 추가할 유저 존재 여부 확인
+	newMembers := []primitive.ObjectID{}
+	for _, userID := range uniqueObjectIDs(userIDs) {
+		if containsObjectID(room.Members, userID) {
+			continue
+		}
+		if _, err := s.messageRepo.GetUserByID(userID); err != nil {
+			return ErrUserNotFound
+		}
+		newMembers = append(newMembers, userID)
+	}
+	if len(newMembers) == 0 {
+		return nil
+	}
+
+	if err := s.chatRepo.AddMembers(room.ID, newMembers); err != nil {
+		return err
+	}
+
+	for _, userID := range newMembers {
+		s.publishMemberEvent(room.ID, models.EventMemberAdded, userID, actor)
+	}
+	return nil
+}
+
+// RemoveMember 채팅방에서 멤버 내보내기
+// 방장은 누구든, 관리자는 일반 멤버만 내보낼 수 있다.
+func (s *ChatService) RemoveMember(roomID, actorID, userID string) error {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+	target, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return ErrNotRoomMember
+	}
+	if target == actor {
+		return s.LeaveRoom(roomID, actorID)
+	}
+
+	actorRole := RoomRole(room, actor)
+	targetRole := RoomRole(room, target)
+	switch {
+	case targetRole == "":
+		return ErrNotRoomMember
+	case actorRole == models.RoomRoleOwner:
+		// 방장은 누구든 내보낼 수 있음
+	case actorRole == models.RoomRoleAdmin && targetRole == models.RoomRoleMember:
+		// 관리자는 일반 멤버만 내보낼 수 있음
+	default:
+		return ErrRoomPermissionDenied
+	}
+
+	removed, err := s.chatRepo.RemoveMember(room.ID, target)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotRoomMember
+	}
+
+	if err := s.messageRepo.DeleteUserMentionsInRoom(room.ID, target); err != nil {
+		log.Printf("Failed to delete mentions of user %s in room %s: %v", userID, room.ID.Hex(), err)
+	}
+
+	s.publishMemberEvent(room.ID, models.EventMemberRemoved, target, actor)
+	s.manager.DisconnectUserFromRoom(room.ID.Hex(), userID)
+	return nil
+}
+
+// LeaveRoom 채팅방 나가기
+func (s *ChatService) LeaveRoom(roomID, userID string) error {
+	room, actor, err := s.getMembershipRoom(roomID, userID)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID == actor {
+		return ErrOwnerCannotLeave
+	}
+
+	removed, err := s.chatRepo.RemoveMember(room.ID, actor)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrNotRoomMember
+	}
+
+	if err := s.messageRepo.DeleteUserMentionsInRoom(room.ID, actor); err != nil {
+		log.Printf("Failed to delete mentions of user %s in room %s: %v", userID, room.ID.Hex(), err)
+	}
+
+	s.publishMemberEvent(room.ID, models.EventMemberLeft, actor, actor)
+	s.manager.DisconnectUserFromRoom(room.ID.Hex(), userID)
+	return nil
+}
+
+// SetMemberRole 멤버의 관리자 권한 변경 (방장만 가능)
+func (s *ChatService) SetMemberRole(roomID, actorID, userID, role string) error {
+	if role != models.RoomRoleAdmin && role != models.RoomRoleMember {
+		return ErrInvalidRoomRole
+	}
+
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+	if room.OwnerID != actor {
+		return ErrRoomPermissionDenied
+	}
+
+	target, err := primitive.ObjectIDFromHex(userID)
+	if err != nil || target == room.OwnerID {
+		return ErrInvalidRoomRole
+	}
+
+	updated, err := s.chatRepo.SetAdmin(room.ID, target, role == models.RoomRoleAdmin)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrNotRoomMember
+	}
+
+	s.publishEvent(room.ID, models.EventMemberRoleChanged, map[string]interface{}{
+		"user_id":   target,
+		"user_name": s.userName(target),
+		"role":      role,
+		"actor_id":  actor,
+	})
+	return nil
+}
+
+// TransferOwnership 방장 위임 (기존 방장은 관리자가 됨)
+func (s *ChatService) TransferOwnership(roomID, actorID, newOwnerID string) error {
+	room, actor, err := s.getMembershipRoom(roomID, actorID)
+	if err != nil {
+		return err
+	}
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
+	if room.OwnerID != actor {
+		return ErrRoomPermissionDenied
+	}
+
+	newOwner, err := primitive.ObjectIDFromHex(newOwnerID)
+	if err != nil || !containsObjectID(room.Members, newOwner) {
+		return ErrNotRoomMember
+	}
+	if newOwner == actor {
+		return nil
+	}
+
+	admins := []primitive.ObjectID{actor}
+	for _, adminID := range room.Admins {
+		if adminID != newOwner && adminID != actor {
+			admins = append(admins, adminID)
+		}
+	}
+
+	transferred, err := s.chatRepo.TransferOwnership(room.ID, actor, newOwner, admins)
+	if err != nil {
+		return err
+	}
+	if !transferred {
+		return ErrRoomPermissionDenied
+	}
+
+	s.publishEvent(room.ID, models.EventOwnerChanged, map[string]interface{}{
+		"previous_owner_id": actor,
+		"owner_id":          newOwner,
+		"owner_name":        s.userName(newOwner),
+	})
+	return nil
+}
+
+// 채팅방과 요청한 유저를 조회하고 멤버인지 확인
+// 1:1 대화방은 멤버 구성을 바꿀 수 없으므로 거부한다.
+func (s *ChatService) getMembershipRoom(roomID, actorID string) (*models.ChatRoom, primitive.ObjectID, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, actorID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	if room.IsDirect() {
+		return nil, primitive.NilObjectID, ErrDirectRoomMembership
+	}
+	actor, _ := primitive.ObjectIDFromHex(actorID)
+	return room, actor, nil
+}
+
+// 멤버 변경 이벤트 브로드캐스트
+func (s *ChatService) publishMemberEvent(roomID primitive.ObjectID, eventType string, userID, actorID primitive.ObjectID) {
+	s.publishEvent(roomID, eventType, map[string]interface{}{
+		"user_id":   userID,
+		"user_name": s.userName(userID),
+		"actor_id":  actorID,
+	})
+}
+
+// 채팅방 이벤트 브로드캐스트 (실패해도 요청은 성공으로 처리)
+func (s *ChatService) publishEvent(roomID primitive.ObjectID, eventType string, data interface{}) {
+	event := &models.RoomEvent{
+		Type:      eventType,
+		RoomID:    roomID.Hex(),
+		Data:      data,
+		CreatedAt: time.Now().Unix(),
+	}
+	if err := s.manager.BroadcastEventToRoom(roomID.Hex(), event); err != nil {
+		log.Printf("Failed to broadcast %s event: %v", eventType, err)
+	}
+}
+
+func (s *ChatService) userName(userID primitive.ObjectID) string {
+	name, _ := s.GetUserName(userID)
+	return name
+}
+
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// 중복 제거 (순서 유지)
+func uniqueObjectIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	result := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
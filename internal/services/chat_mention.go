@@ -0,0 +1,303 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"chat-go-api/internal/utils"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrMentionNotFound = errors.New("mention not found")
+
+const (
+	maxMentionsPerMessage = 50
+	defaultMentionLimit   = 30
+	maxMentionLimit       = 100
+)
+
+// @ 앞에 글자가 오면 이메일 주소 등으로 보고 멘션으로 취급하지 않는다.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([\p{L}\p{N}_][\p{L}\p{N}_.\-]*)`)
+
+// mentionToken 메시지 내용에서 찾은 @이름
+type mentionToken struct {
+	name   string
+	offset int // 문자(rune) 단위
+	length int
+}
+
+// 메시지 내용에서 @이름 찾기 (끝에 붙은 마침표와 하이픈은 문장 부호로 보고 제외)
+func parseMentions(content string) []mentionToken {
+	var tokens []mentionToken
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(content, maxMentionsPerMessage) {
+		name := strings.TrimRight(content[match[2]:match[3]], ".-")
+		if name == "" {
+			continue
+		}
+		start := match[2] - 1 // @ 위치
+		tokens = append(tokens, mentionToken{
+			name:   name,
+			offset: utf8.RuneCountInString(content[:start]),
+			length: utf8.RuneCountInString(name) + 1,
+		})
+	}
+	return tokens
+}
+
+// 멘션 비교용 이름 (공백 제거, 소문자)
+func mentionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// ResolveMentions 메시지 내용의 @이름, @here, @room을 채팅방 멤버로 확인해 메시지에 저장
+// @이름은 공백을 뺀 이름 또는 이메일 아이디와 대소문자 구분 없이 비교하며, 멤버가 아니면 무시한다.
+// @here는 보내는 시점에 접속 중인 멤버, @room은 멤버 전체를 멘션한다.
+func (s *ChatService) ResolveMentions(room *models.ChatRoom, msg *models.Message) error {
+	msg.Mentions, msg.MentionedUserIDs = nil, nil
+	tokens := parseMentions(msg.Content)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	users, err := s.messageRepo.GetUsersByIDs(room.Members)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string][]primitive.ObjectID)
+	for _, user := range users {
+		byName[mentionKey(user.Name)] = append(byName[mentionKey(user.Name)], user.ID)
+		if local, _, ok := strings.Cut(user.Email, "@"); ok {
+			key := mentionKey(local)
+			if !containsObjectID(byName[key], user.ID) {
+				byName[key] = append(byName[key], user.ID)
+			}
+		}
+	}
+
+	var mentioned []primitive.ObjectID
+	for _, token := range tokens {
+		switch key := mentionKey(token.name); key {
+		case models.MentionTypeHere, models.MentionTypeRoom:
+			msg.Mentions = append(msg.Mentions, models.MessageMention{Type: key, Offset: token.offset, Length: token.length})
+			for _, memberID := range room.Members {
+				if key == models.MentionTypeRoom || s.manager.IsUserConnected(memberID.Hex()) {
+					mentioned = append(mentioned, memberID)
+				}
+			}
+		default:
+			for _, userID := range byName[key] {
+				userID := userID
+				msg.Mentions = append(msg.Mentions, models.MessageMention{
+					Type:   models.MentionTypeUser,
+					UserID: &userID,
+					Offset: token.offset,
+					Length: token.length,
+				})
+				mentioned = append(mentioned, userID)
+			}
+		}
+	}
+
+	// 작성자 본인은 멘션 알림 대상에서 제외
+	for _, userID := range uniqueObjectIDs(mentioned) {
+		if userID != msg.SenderID {
+			msg.MentionedUserIDs = append(msg.MentionedUserIDs, userID)
+		}
+	}
+	return nil
+}
+
+// 멘션된 유저의 멘션 보관함에 추가하고, 접속 중이면 어느 채팅방에 있든 mention.created 이벤트 전송
+// onlyUserIDs가 nil이 아니면 해당 유저에게만 전달한다. (메시지 수정으로 새로 멘션된 유저)
+// 저장과 전송은 메시지 전송 응답을 늦추지 않도록 백그라운드에서 처리한다.
+func (s *ChatService) deliverMentions(msg *models.Message, onlyUserIDs []primitive.ObjectID) {
+	if len(msg.MentionedUserIDs) == 0 {
+		return
+	}
+
+	snippet := messageSnippet(msg)
+	mentions := make([]*models.Mention, 0, len(msg.MentionedUserIDs))
+	for _, userID := range msg.MentionedUserIDs {
+		if onlyUserIDs != nil && !containsObjectID(onlyUserIDs, userID) {
+			continue
+		}
+		mentions = append(mentions, &models.Mention{
+			UserID:    userID,
+			RoomID:    msg.RoomID,
+			MessageID: msg.ID,
+			SenderID:  msg.SenderID,
+			Type:      mentionTypeFor(msg.Mentions, userID),
+			Snippet:   snippet,
+			CreatedAt: msg.CreatedAt,
+		})
+	}
+
+	if len(mentions) == 0 {
+		return
+	}
+	go s.saveMentions(msg.ID, msg.RoomID, mentions)
+}
+
+func (s *ChatService) saveMentions(messageID, roomID primitive.ObjectID, mentions []*models.Mention) {
+	inserted, err := s.messageRepo.InsertMentions(mentions)
+	if err != nil {
+		log.Printf("Failed to save mentions of message %s: %v", messageID.Hex(), err)
+	}
+	if len(inserted) == 0 {
+		return
+	}
+
+	roomName := s.roomName(roomID)
+	for _, mention := range inserted {
+		dto := s.toMentionDTO(mention, roomName)
+		s.manager.SendEventToUser(mention.UserID.Hex(), &models.RoomEvent{
+			Type:      models.EventMentionCreated,
+			RoomID:    mention.RoomID.Hex(),
+			Data:      dto,
+			CreatedAt: time.Now().Unix(),
+		})
+	}
+}
+
+// 유저가 멘션된 방식 (직접 멘션 > @room > @here 순으로 우선)
+func mentionTypeFor(mentions []models.MessageMention, userID primitive.ObjectID) string {
+	mentionType := models.MentionTypeHere
+	for _, mention := range mentions {
+		switch {
+		case mention.Type == models.MentionTypeUser && mention.UserID != nil && *mention.UserID == userID:
+			return models.MentionTypeUser
+		case mention.Type == models.MentionTypeRoom:
+			mentionType = models.MentionTypeRoom
+		}
+	}
+	return mentionType
+}
+
+// MentionQuery 멘션 보관함 조회 조건
+type MentionQuery struct {
+	UnreadOnly bool
+	Cursor     string // 이전 응답의 next_cursor
+	Limit      int64
+}
+
+// MentionPage 멘션 보관함 페이지 (최신순)
+type MentionPage struct {
+	Mentions    []*models.MentionDTO `json:"mentions"`
+	UnreadCount int64                `json:"unread_count"` // 읽지 않은 멘션 수 (채팅방별 안 읽은 메시지 수와 별개)
+	NextCursor  string               `json:"next_cursor,omitempty"`
+}
+
+// GetMentions 멘션 보관함 조회
+func (s *ChatService) GetMentions(userID string, query MentionQuery) (*MentionPage, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultMentionLimit
+	}
+	if limit > maxMentionLimit {
+		limit = maxMentionLimit
+	}
+
+	var before *repository.MessageAnchor
+	if query.Cursor != "" {
+		ts, id, err := utils.DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		before = &repository.MessageAnchor{CreatedAt: ts, ID: id}
+	}
+
+	// 다음 페이지 존재 여부 확인을 위해 하나 더 조회
+	mentions, err := s.messageRepo.GetMentions(uid, query.UnreadOnly, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	page := &MentionPage{Mentions: []*models.MentionDTO{}}
+	if int64(len(mentions)) > limit {
+		mentions = mentions[:limit]
+		last := mentions[len(mentions)-1]
+		page.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	roomNames := make(map[primitive.ObjectID]string)
+	for _, mention := range mentions {
+		name, ok := roomNames[mention.RoomID]
+		if !ok {
+			name = s.roomName(mention.RoomID)
+			roomNames[mention.RoomID] = name
+		}
+		page.Mentions = append(page.Mentions, s.toMentionDTO(mention, name))
+	}
+
+	page.UnreadCount, err = s.messageRepo.CountUnreadMentions(uid, maxUnreadCount)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// MarkMentionRead 멘션 하나를 읽음 처리
+func (s *ChatService) MarkMentionRead(userID, mentionID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	id, err := primitive.ObjectIDFromHex(mentionID)
+	if err != nil {
+		return ErrMentionNotFound
+	}
+	err = s.messageRepo.MarkMentionRead(uid, id, time.Now().Unix())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrMentionNotFound
+	}
+	return err
+}
+
+// MarkAllMentionsRead 멘션 보관함 전체 읽음 처리
+func (s *ChatService) MarkAllMentionsRead(userID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	return s.messageRepo.MarkMentionsRead(uid, nil, nil, time.Now().Unix())
+}
+
+func (s *ChatService) toMentionDTO(mention *models.Mention, roomName string) *models.MentionDTO {
+	return &models.MentionDTO{
+		ID:         mention.ID,
+		RoomID:     mention.RoomID,
+		RoomName:   roomName,
+		MessageID:  mention.MessageID,
+		SenderID:   mention.SenderID,
+		SenderName: s.userName(mention.SenderID),
+		Type:       mention.Type,
+		Snippet:    mention.Snippet,
+		CreatedAt:  mention.CreatedAt,
+		ReadAt:     mention.ReadAt,
+	}
+}
+
+// 멘션 보관함에 표시할 채팅방 이름 (1:1 대화방은 이름이 없음)
+func (s *ChatService) roomName(roomID primitive.ObjectID) string {
+	room, err := s.chatRepo.GetChatRoomByID(roomID)
+	if err != nil {
+		return ""
+	}
+	return room.Name
+}
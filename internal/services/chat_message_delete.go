@@ -0,0 +1,88 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/utils"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMessageDeleted         = errors.New("message has been deleted")
+	ErrMessageDeleteForbidden = errors.New("you cannot delete this message")
+)
+
+// DeleteMessage 메시지 삭제 (작성자 또는 방장/관리자)
+// 메시지는 내용이 지워진 tombstone으로 남아 페이지 위치와 스레드 구조가 유지되며,
+// 원래 내용과 수정 이력은 메시지에서 즉시 지워지고, 보관 기간이 설정되어 있으면 그동안 관리용으로만 보관된다.
+// 첨부파일은 백그라운드에서 원본까지 삭제된다.
+func (s *ChatService) DeleteMessage(roomID, userID, messageID string) (*models.MessageDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+
+	message, err := s.getRoomMessage(room.ID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, ErrMessageDeleted
+	}
+
+	actor, _ := primitive.ObjectIDFromHex(userID)
+	isSender := message.SenderID == actor && message.Type != models.MessageTypeSystem
+	moderated := !isSender
+	if moderated && !canManageMembers(room, actor) {
+		return nil, ErrMessageDeleteForbidden
+	}
+
+	now := time.Now()
+	var retainUntil time.Time
+	if s.options.DeletedMessageRetention > 0 {
+		retainUntil = now.Add(s.options.DeletedMessageRetention)
+	}
+	deleted, err := s.messageRepo.DeleteMessage(message.ID, actor, now.Unix(), retainUntil)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMessageDeleted
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.chatRepo.MarkLastMessageDeleted(room.ID, deleted.ID); err != nil {
+		log.Printf("Failed to update last message of room %s: %v", room.ID.Hex(), err)
+	}
+	if err := s.searcher.Remove(deleted.ID); err != nil {
+		log.Printf("Failed to remove message %s from search index: %v", deleted.ID.Hex(), err)
+	}
+	go s.purgeMessageAttachments(deleted.ID)
+	if deleted.IsReply() {
+		s.removeThreadReply(deleted)
+	}
+
+	s.publishEvent(room.ID, models.EventMessageDeleted, map[string]interface{}{
+		"message_id": deleted.ID,
+		"parent_id":  deleted.ParentID,
+		"deleted_by": actor,
+		"deleted_at": deleted.DeletedAt,
+		"moderated":  moderated,
+	})
+	return utils.ToMessageDTO(deleted, s.GetUserName)
+}
+
+// 삭제된 답글을 부모 메시지의 스레드 요약에서 빼고 thread.updated 이벤트 전송
+func (s *ChatService) removeThreadReply(reply *models.Message) {
+	parent, err := s.messageRepo.RemoveThreadReply(*reply.ParentID, reply.CreatedAt)
+	if err != nil {
+		log.Printf("Failed to update thread of message %s: %v", reply.ParentID.Hex(), err)
+		return
+	}
+	s.publishThreadUpdate(parent)
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/utils"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrInvalidMessageContent    = errors.New("message content cannot be empty")
+	ErrMessageEditForbidden     = errors.New("only the sender can edit this message")
+	ErrMessageEditWindowExpired = errors.New("message can no longer be edited")
+)
+
+// EditMessage 메시지 내용 수정 (작성자만, 설정된 수정 가능 시간 내)
+// 수정 전 내용은 수정 이력으로 남기고 message.updated 이벤트를 브로드캐스트한다.
+// 멘션은 수정된 내용으로 다시 찾아 새로 멘션된 유저에게만 알린다.
+func (s *ChatService) EditMessage(roomID, userID, messageID, content string) (*models.MessageDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, ErrInvalidMessageContent
+	}
+
+	message, err := s.getRoomMessage(room.ID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, ErrMessageDeleted
+	}
+	editor, _ := primitive.ObjectIDFromHex(userID)
+	if message.SenderID != editor || message.Type == models.MessageTypeSystem {
+		return nil, ErrMessageEditForbidden
+	}
+
+	now := time.Now()
+	if s.options.EditWindow > 0 && now.Sub(time.Unix(message.CreatedAt, 0)) > s.options.EditWindow {
+		return nil, ErrMessageEditWindowExpired
+	}
+	if content == message.Content {
+		return utils.ToMessageDTO(message, s.GetUserName)
+	}
+
+	// 수정된 내용으로 멘션을 다시 찾음
+	edited := *message
+	edited.Content = content
+	if err := s.ResolveMentions(room, &edited); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.messageRepo.EditMessage(message.ID, editor, content, edited.Mentions, edited.MentionedUserIDs, now.Unix())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.chatRepo.UpdateLastMessageSnippet(room.ID, updated.ID, messageSnippet(updated)); err != nil {
+		log.Printf("Failed to update last message of room %s: %v", room.ID.Hex(), err)
+	}
+
+	s.indexMessage(updated)
+	s.updateEditedMentions(message, updated)
+
+	messageDTO, err := utils.ToMessageDTO(updated, s.GetUserName)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(room.ID, models.EventMessageUpdated, messageDTO)
+	return messageDTO, nil
+}
+
+// 수정으로 빠진 멘션은 보관함에서 지우고, 새로 멘션된 유저에게만 알림
+func (s *ChatService) updateEditedMentions(previous, updated *models.Message) {
+	if err := s.messageRepo.DeleteMentionsExcept(updated.ID, updated.MentionedUserIDs); err != nil {
+		log.Printf("Failed to delete mentions of message %s: %v", updated.ID.Hex(), err)
+	}
+	if err := s.messageRepo.UpdateMentionSnippet(updated.ID, messageSnippet(updated)); err != nil {
+		log.Printf("Failed to update mentions of message %s: %v", updated.ID.Hex(), err)
+	}
+
+	added := []primitive.ObjectID{}
+	for _, userID := range updated.MentionedUserIDs {
+		if !containsObjectID(previous.MentionedUserIDs, userID) {
+			added = append(added, userID)
+		}
+	}
+	if len(added) > 0 {
+		s.deliverMentions(updated, added)
+	}
+}
+
+// GetMessageEdits 메시지 수정 이력 조회 (채팅방 멤버만)
+func (s *ChatService) GetMessageEdits(roomID, userID, messageID string) ([]*models.MessageEdit, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	message, err := s.getRoomMessage(room.ID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	return s.messageRepo.GetMessageEdits(message.ID)
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"chat-go-api/internal/utils"
+	"errors"
+)
+
+var ErrInvalidMessageQuery = errors.New("only one of before, after and around can be used")
+
+// MessageCursorQuery 커서 기반 메시지 조회 조건
+// Before/After는 이전 응답의 커서, Around는 기준이 되는 메시지 ID이며 셋 중 하나만 사용할 수 있다.
+// 아무것도 지정하지 않으면 가장 최근 메시지부터 조회한다.
+type MessageCursorQuery struct {
+	Before      string
+	After       string
+	Around      string
+	Limit       int64
+	HideReplies bool
+}
+
+// MessagePage 커서 기반 메시지 페이지 (메시지는 오래된 순)
+// PrevCursor는 더 이전 메시지가, NextCursor는 더 이후 메시지가 있을 때만 채워진다.
+type MessagePage struct {
+	Messages   []*models.MessageDTO `json:"messages"`
+	PrevCursor string               `json:"prev_cursor,omitempty"` // before 파라미터로 사용
+	NextCursor string               `json:"next_cursor,omitempty"` // after 파라미터로 사용
+}
+
+// GetMessagesByCursor 커서 기반 메시지 조회 (채팅방 멤버만)
+// skip 없이 (작성 시각, ID) 기준으로 조회하므로 채팅방이 커져도 느려지지 않고,
+// 새 메시지가 들어와도 페이지가 밀리지 않는다.
+func (s *ChatService) GetMessagesByCursor(roomID, userID string, query MessageCursorQuery) (*MessagePage, error) {
+	anchors := 0
+	for _, v := range []string{query.Before, query.After, query.Around} {
+		if v != "" {
+			anchors++
+		}
+	}
+	if anchors > 1 {
+		return nil, ErrInvalidMessageQuery
+	}
+
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var older, newer []*models.Message
+	var hasOlder, hasNewer bool
+	switch {
+	case query.After != "":
+		anchor, err := decodeMessageAnchor(query.After)
+		if err != nil {
+			return nil, err
+		}
+		newer, hasNewer, err = s.messagesAfter(room, anchor, false, query.Limit, query.HideReplies)
+		if err != nil {
+			return nil, err
+		}
+		hasOlder = true // 커서 위치 이전 메시지가 존재함
+
+	case query.Around != "":
+		target, err := s.getRoomMessage(room.ID, query.Around)
+		if err != nil {
+			return nil, err
+		}
+		anchor := repository.MessageAnchor{CreatedAt: target.CreatedAt, ID: target.ID}
+		olderLimit := query.Limit / 2
+		if olderLimit > 0 {
+			if older, hasOlder, err = s.messagesBefore(room, &anchor, olderLimit, query.HideReplies); err != nil {
+				return nil, err
+			}
+		} else {
+			hasOlder = true
+		}
+		// 기준 메시지는 이후 구간에 포함
+		if newer, hasNewer, err = s.messagesAfter(room, anchor, true, query.Limit-olderLimit, query.HideReplies); err != nil {
+			return nil, err
+		}
+
+	default:
+		var anchor *repository.MessageAnchor
+		if query.Before != "" {
+			decoded, err := decodeMessageAnchor(query.Before)
+			if err != nil {
+				return nil, err
+			}
+			anchor = &decoded
+			hasNewer = true // 커서 위치 이후 메시지가 존재함
+		}
+		if older, hasOlder, err = s.messagesBefore(room, anchor, query.Limit, query.HideReplies); err != nil {
+			return nil, err
+		}
+	}
+
+	messages := append(older, newer...)
+	page := &MessagePage{Messages: []*models.MessageDTO{}}
+	for _, message := range messages {
+		dto, err := utils.ToMessageDTO(message, s.GetUserName)
+		if err != nil {
+			return nil, err
+		}
+		page.Messages = append(page.Messages, dto)
+	}
+	if len(messages) > 0 {
+		first, last := messages[0], messages[len(messages)-1]
+		if hasOlder {
+			page.PrevCursor = utils.EncodeCursor(first.CreatedAt, first.ID)
+		}
+		if hasNewer {
+			page.NextCursor = utils.EncodeCursor(last.CreatedAt, last.ID)
+		}
+	} else if query.Before != "" || query.After != "" {
+		// 결과가 없으면 받은 커서를 그대로 돌려주어 같은 위치에서 다시 조회할 수 있게 함
+		page.PrevCursor, page.NextCursor = query.Before, query.After
+	}
+	return page, nil
+}
+
+// 기준 위치 이전 메시지를 오래된 순으로 반환 (더 이전 메시지가 있는지 함께 반환)
+func (s *ChatService) messagesBefore(room *models.ChatRoom, anchor *repository.MessageAnchor, limit int64, hideReplies bool) ([]*models.Message, bool, error) {
+	messages, err := s.messageRepo.GetMessagesBefore(room.ID, anchor, limit+1, hideReplies)
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := int64(len(messages)) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, hasMore, nil
+}
+
+// 기준 위치 이후 메시지를 오래된 순으로 반환 (더 이후 메시지가 있는지 함께 반환)
+func (s *ChatService) messagesAfter(room *models.ChatRoom, anchor repository.MessageAnchor, inclusive bool, limit int64, hideReplies bool) ([]*models.Message, bool, error) {
+	messages, err := s.messageRepo.GetMessagesAfter(room.ID, anchor, inclusive, limit+1, hideReplies)
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := int64(len(messages)) > limit
+	if hasMore {
+		messages = messages[:limit]
+	}
+	return messages, hasMore, nil
+}
+
+func decodeMessageAnchor(cursor string) (repository.MessageAnchor, error) {
+	createdAt, id, err := utils.DecodeCursor(cursor)
+	if err != nil {
+		return repository.MessageAnchor{}, err
+	}
+	return repository.MessageAnchor{CreatedAt: createdAt, ID: id}, nil
+}
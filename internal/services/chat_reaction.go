@@ -0,0 +1,100 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxReactionLength = 32 // 리액션 최대 바이트 수 (ZWJ로 결합된 이모지 포함)
+
+var (
+	ErrInvalidReaction  = errors.New("invalid reaction")
+	ErrReactionExists   = errors.New("you already added this reaction")
+	ErrReactionNotFound = errors.New("reaction not found")
+)
+
+// AddReaction 메시지에 리액션 추가 (채팅방 멤버만, 같은 리액션은 한 번만)
+func (s *ChatService) AddReaction(roomID, userID, messageID, emoji string) (*models.ReactionDTO, error) {
+	return s.changeReaction(roomID, userID, messageID, emoji, true)
+}
+
+// RemoveReaction 메시지에서 자신의 리액션 제거
+func (s *ChatService) RemoveReaction(roomID, userID, messageID, emoji string) (*models.ReactionDTO, error) {
+	return s.changeReaction(roomID, userID, messageID, emoji, false)
+}
+
+// 리액션을 추가/제거하고 변경된 리액션의 집계를 이벤트로 브로드캐스트
+// 중복 여부는 DB 조건부 업데이트로 판단하므로 동시에 요청해도 한 번만 반영된다.
+func (s *ChatService) changeReaction(roomID, userID, messageID, emoji string, add bool) (*models.ReactionDTO, error) {
+	if !isValidReaction(emoji) {
+		return nil, ErrInvalidReaction
+	}
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	message, err := s.getRoomMessage(room.ID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message.IsDeleted() {
+		return nil, ErrMessageDeleted
+	}
+
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	eventType := models.EventReactionAdded
+	var updated *models.Message
+	if add {
+		updated, err = s.messageRepo.AddReaction(message.ID, uid, emoji)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrReactionExists
+		}
+	} else {
+		eventType = models.EventReactionRemoved
+		updated, err = s.messageRepo.RemoveReaction(message.ID, uid, emoji)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrReactionNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := updated.Reactions[emoji]
+	if userIDs == nil {
+		userIDs = []primitive.ObjectID{}
+	}
+	reaction := &models.ReactionDTO{Emoji: emoji, Count: len(userIDs), UserIDs: userIDs}
+	s.publishEvent(room.ID, eventType, map[string]interface{}{
+		"message_id": message.ID,
+		"user_id":    uid,
+		"emoji":      emoji,
+		"count":      reaction.Count,
+	})
+	return reaction, nil
+}
+
+// 리액션 값 검증
+// MongoDB 필드 경로로 사용되므로 '.'과 '$'로 시작하는 값, 공백, 제어 문자는 허용하지 않는다.
+func isValidReaction(emoji string) bool {
+	if emoji == "" || len(emoji) > maxReactionLength {
+		return false
+	}
+	if strings.Contains(emoji, ".") || strings.HasPrefix(emoji, "$") {
+		return false
+	}
+	for _, r := range emoji {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 안 읽은 메시지 수는 이 값까지만 센다 (클라이언트는 "999+"로 표시)
+const maxUnreadCount = 1000
+
+// MarkRoomRead 채팅방을 지정한 메시지까지 읽음 처리 (messageID가 비어 있으면 최신 메시지까지)
+// 읽음 위치는 유저당 문서 하나만 갱신하며, 위치가 앞으로 이동한 경우에만 이벤트를 브로드캐스트한다.
+// 해당 위치까지의 멘션도 멘션 보관함에서 읽음 처리된다.
+func (s *ChatService) MarkRoomRead(roomID, userID, messageID string) (*models.ReadReceiptDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var message *models.Message
+	if messageID == "" {
+		message, err = s.messageRepo.GetLatestMessage(room.ID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrMessageNotFound
+		}
+	} else {
+		message, err = s.getRoomMessage(room.ID, messageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uid, _ := primitive.ObjectIDFromHex(userID)
+	position := &models.ReadPosition{
+		RoomID:            room.ID,
+		UserID:            uid,
+		LastReadMessageID: message.ID,
+		LastReadAt:        message.CreatedAt,
+		UpdatedAt:         time.Now().Unix(),
+	}
+	advanced, err := s.chatRepo.AdvanceReadPosition(position)
+	if err != nil {
+		return nil, err
+	}
+
+	// 읽은 위치까지의 멘션도 읽음 처리
+	upTo := &repository.MessageAnchor{CreatedAt: message.CreatedAt, ID: message.ID}
+	if err := s.messageRepo.MarkMentionsRead(uid, &room.ID, upTo, position.UpdatedAt); err != nil {
+		log.Printf("Failed to mark mentions of room %s as read: %v", room.ID.Hex(), err)
+	}
+
+	receipt := s.toReadReceiptDTO(position)
+	if advanced {
+		s.publishEvent(room.ID, models.EventReadUpdated, receipt)
+	}
+	return receipt, nil
+}
+
+// GetReadReceipts 채팅방 멤버들의 읽음 위치 조회 ("seen by" 표시용)
+func (s *ChatService) GetReadReceipts(roomID, userID string) ([]*models.ReadReceiptDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.chatRepo.GetReadPositionsByRoom(room.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := []*models.ReadReceiptDTO{}
+	for _, position := range positions {
+		// 채팅방을 나간 유저의 읽음 위치는 제외
+		if !containsObjectID(room.Members, position.UserID) {
+			continue
+		}
+		receipts = append(receipts, s.toReadReceiptDTO(position))
+	}
+	return receipts, nil
+}
+
+// 채팅방 목록에 안 읽은 메시지 수와 멘션 수 채우기
+func (s *ChatService) fillUnreadCounts(userID primitive.ObjectID, roomDTOs []*models.ChatRoomDTO) error {
+	if len(roomDTOs) == 0 {
+		return nil
+	}
+
+	roomIDs := make([]primitive.ObjectID, 0, len(roomDTOs))
+	for _, dto := range roomDTOs {
+		roomIDs = append(roomIDs, dto.ID)
+	}
+	positions, err := s.chatRepo.GetReadPositionsByUser(userID, roomIDs)
+	if err != nil {
+		return err
+	}
+
+	counts, err := s.messageRepo.CountUnreadMessagesByRoom(userID, roomIDs, positions, maxUnreadCount)
+	if err != nil {
+		return err
+	}
+	for _, dto := range roomDTOs {
+		dto.UnreadCount = counts[dto.ID].Unread
+		dto.MentionCount = counts[dto.ID].Mentions
+	}
+	return nil
+}
+
+func (s *ChatService) toReadReceiptDTO(position *models.ReadPosition) *models.ReadReceiptDTO {
+	return &models.ReadReceiptDTO{
+		UserID:            position.UserID,
+		UserName:          s.userName(position.UserID),
+		LastReadMessageID: position.LastReadMessageID,
+		LastReadAt:        position.LastReadAt,
+		UpdatedAt:         position.UpdatedAt,
+	}
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	maxRoomNameLength        = 100
+	maxRoomTopicLength       = 250
+	maxRoomDescriptionLength = 1000
+	maxAvatarURLLength       = 2048
+)
+
+var (
+	ErrInvalidRoomMetadata   = errors.New("invalid room metadata")
+	ErrDirectRoomNotEditable = errors.New("direct message rooms cannot be edited")
+)
+
+// RoomMetadataUpdate 채팅방 메타데이터 수정 요청 (nil이면 변경하지 않음)
+type RoomMetadataUpdate struct {
+	Name        *string
+	Topic       *string
+	Description *string
+	AvatarURL   *string
+	Visibility  *string
+}
+
+// UpdateRoomMetadata 채팅방 이름/주제/설명/아바타 수정
+// 변경된 항목마다 시스템 메시지를 남기고 채팅방에 브로드캐스트한다.
+func (s *ChatService) UpdateRoomMetadata(roomID, actorID string, update RoomMetadataUpdate) (*models.ChatRoomDTO, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if room.IsDirect() {
+		return nil, ErrDirectRoomNotEditable
+	}
+	if room.IsArchived() {
+		return nil, ErrRoomArchived
+	}
+	actor, _ := primitive.ObjectIDFromHex(actorID)
+	if !canManageMembers(room, actor) {
+		return nil, ErrRoomPermissionDenied
+	}
+
+	if err := validateRoomMetadata(update); err != nil {
+		return nil, err
+	}
+
+	actorName := s.userName(actor)
+	fields := bson.M{}
+	notices := []string{}
+
+	if update.Name != nil && strings.TrimSpace(*update.Name) != room.Name {
+		name := strings.TrimSpace(*update.Name)
+		fields["name"] = name
+		notices = append(notices, fmt.Sprintf("%s renamed the room to %s", actorName, name))
+	}
+	if update.Topic != nil && strings.TrimSpace(*update.Topic) != room.Topic {
+		topic := strings.TrimSpace(*update.Topic)
+		fields["topic"] = topic
+		if topic == "" {
+			notices = append(notices, fmt.Sprintf("%s cleared the topic", actorName))
+		} else {
+			notices = append(notices, fmt.Sprintf("%s changed the topic to %s", actorName, topic))
+		}
+	}
+	if update.Description != nil && *update.Description != room.Description {
+		fields["description"] = *update.Description
+		notices = append(notices, fmt.Sprintf("%s updated the room description", actorName))
+	}
+	if update.AvatarURL != nil && *update.AvatarURL != room.AvatarURL {
+		fields["avatar_url"] = *update.AvatarURL
+		notices = append(notices, fmt.Sprintf("%s changed the room avatar", actorName))
+	}
+
+	if update.Visibility != nil && *update.Visibility != visibilityOf(room) {
+		fields["visibility"] = *update.Visibility
+		notices = append(notices, fmt.Sprintf("%s changed the room visibility to %s", actorName, *update.Visibility))
+	}
+
+	// 변경 사항이 없으면 현재 상태 그대로 반환
+	if len(fields) == 0 {
+		return s.ToChatRoomDTO(room, actorID)
+	}
+
+	fields["updated_at"] = time.Now().Unix()
+	updated, err := s.chatRepo.UpdateChatRoom(room.ID, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// 비공개로 전환되면 대기 중인 참여 요청은 더 이상 승인될 수 없으므로 거절 처리
+	if update.Visibility != nil && visibilityOf(updated) == models.RoomVisibilityPrivate {
+		if err := s.chatRepo.DenyPendingJoinRequests(room.ID, actor); err != nil {
+			log.Printf("Failed to deny pending join requests for room %s: %v", room.ID.Hex(), err)
+		}
+	}
+
+	for _, notice := range notices {
+		if err := s.postSystemMessage(room.ID, actor, notice); err != nil {
+			return nil, err
+		}
+	}
+
+	roomDTO, err := s.ToChatRoomDTO(updated, actorID)
+	if err != nil {
+		return nil, err
+	}
+	s.publishEvent(room.ID, models.EventRoomUpdated, roomDTO)
+	return roomDTO, nil
+}
+
+// 메타데이터 값 검증
+func validateRoomMetadata(update RoomMetadataUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxRoomNameLength {
+			return fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidRoomMetadata, maxRoomNameLength)
+		}
+	}
+	if update.Topic != nil && utf8.RuneCountInString(*update.Topic) > maxRoomTopicLength {
+		return fmt.Errorf("%w: topic must be at most %d characters", ErrInvalidRoomMetadata, maxRoomTopicLength)
+	}
+	if update.Description != nil && utf8.RuneCountInString(*update.Description) > maxRoomDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d characters", ErrInvalidRoomMetadata, maxRoomDescriptionLength)
+	}
+	if update.Visibility != nil && !models.IsValidRoomVisibility(*update.Visibility) {
+		return ErrInvalidRoomVisibility
+	}
+	if update.AvatarURL != nil && *update.AvatarURL != "" {
+		u, err := url.Parse(*update.AvatarURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(*update.AvatarURL) > maxAvatarURLLength {
+			return fmt.Errorf("%w: avatar_url must be an http(s) URL", ErrInvalidRoomMetadata)
+		}
+	}
+	return nil
+}
+
+// 공개 범위 (이전 채팅방은 private)
+func visibilityOf(room *models.ChatRoom) string {
+	if room.Visibility == "" {
+		return models.RoomVisibilityPrivate
+	}
+	return room.Visibility
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"chat-go-api/internal/search"
+	"chat-go-api/internal/utils"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// MessageSearchResult 메시지 검색 결과 페이지
+type MessageSearchResult struct {
+	Results []*models.MessageSearchHitDTO `json:"results"`
+	Total   int64                         `json:"total"`
+	Page    int64                         `json:"page"`
+	Limit   int64                         `json:"limit"`
+}
+
+// SearchMessages 유저가 접근할 수 있는 채팅방의 메시지 검색
+// from:, in: 필터는 ID 또는 이름으로 지정할 수 있으며, 찾을 수 없으면 빈 결과를 반환한다.
+func (s *ChatService) SearchMessages(userID, rawQuery string, limit, page int64) (*MessageSearchResult, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := search.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSearchQuery, err)
+	}
+	if parsed.IsEmpty() {
+		return nil, fmt.Errorf("%w: empty query", ErrInvalidSearchQuery)
+	}
+
+	result := &MessageSearchResult{Results: []*models.MessageSearchHitDTO{}, Page: page, Limit: limit}
+
+	// 보관된 채팅방도 검색 대상에 포함
+	rooms, err := s.chatRepo.GetChatRoomsByUserID(uid, repository.RoomListOptions{Archived: repository.ArchivedInclude})
+	if err != nil {
+		return nil, err
+	}
+	roomIDs := s.resolveSearchRooms(rooms, parsed.In)
+	if len(roomIDs) == 0 {
+		return result, nil
+	}
+
+	var senderIDs []primitive.ObjectID
+	if len(parsed.From) > 0 {
+		if senderIDs, err = s.resolveSearchSenders(uid, parsed.From); err != nil {
+			return nil, err
+		}
+		if len(senderIDs) == 0 {
+			return result, nil
+		}
+	}
+
+	found, err := s.searcher.Search(search.Query{
+		Terms:         parsed.Terms,
+		RoomIDs:       roomIDs,
+		SenderIDs:     senderIDs,
+		After:         parsed.After,
+		Before:        parsed.Before,
+		HasAttachment: parsed.HasAttachment,
+		Limit:         limit,
+		Page:          page,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Total = found.Total
+	if len(found.Hits) == 0 {
+		return result, nil
+	}
+
+	messageIDs := make([]primitive.ObjectID, 0, len(found.Hits))
+	for _, hit := range found.Hits {
+		messageIDs = append(messageIDs, hit.MessageID)
+	}
+	messages, err := s.messageRepo.GetMessagesByIDs(messageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hit := range found.Hits {
+		// 색인 반영 전에 삭제된 메시지는 제외
+		message, ok := messages[hit.MessageID]
+		if !ok || message.IsDeleted() {
+			continue
+		}
+		dto, err := utils.ToMessageDTO(message, s.GetUserName)
+		if err != nil {
+			return nil, err
+		}
+		result.Results = append(result.Results, &models.MessageSearchHitDTO{
+			Message:   dto,
+			Highlight: hit.Highlight,
+			Score:     hit.Score,
+		})
+	}
+	return result, nil
+}
+
+// ReindexSearch 저장된 모든 메시지로 검색 색인 재구성 (프로세스 내 색인 사용 시 시작할 때 호출)
+func (s *ChatService) ReindexSearch() error {
+	count := 0
+	err := s.messageRepo.EachMessage(func(message *models.Message) error {
+		if message.Type == models.MessageTypeSystem {
+			return nil
+		}
+		count++
+		return s.searcher.Index(toSearchDocument(message))
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Indexed %d messages for search", count)
+	return nil
+}
+
+// 검색 색인에 메시지 반영 (시스템 메시지는 제외, 실패해도 요청은 성공으로 처리)
+func (s *ChatService) indexMessage(message *models.Message) {
+	if message.Type == models.MessageTypeSystem {
+		return
+	}
+	if err := s.searcher.Index(toSearchDocument(message)); err != nil {
+		log.Printf("Failed to index message %s: %v", message.ID.Hex(), err)
+	}
+}
+
+// in: 필터를 채팅방 ID로 변환 (필터가 없으면 접근 가능한 모든 채팅방)
+func (s *ChatService) resolveSearchRooms(rooms []models.ChatRoom, filters []string) []primitive.ObjectID {
+	roomIDs := []primitive.ObjectID{}
+	for _, room := range rooms {
+		if len(filters) == 0 {
+			roomIDs = append(roomIDs, room.ID)
+			continue
+		}
+		for _, filter := range filters {
+			if room.ID.Hex() == filter || (room.Name != "" && strings.EqualFold(room.Name, filter)) {
+				roomIDs = append(roomIDs, room.ID)
+				break
+			}
+		}
+	}
+	return roomIDs
+}
+
+// from: 필터를 유저 ID로 변환 ("me"는 검색하는 유저)
+func (s *ChatService) resolveSearchSenders(userID primitive.ObjectID, filters []string) ([]primitive.ObjectID, error) {
+	senderIDs := []primitive.ObjectID{}
+	for _, filter := range filters {
+		if strings.EqualFold(filter, "me") {
+			senderIDs = append(senderIDs, userID)
+			continue
+		}
+		if id, err := primitive.ObjectIDFromHex(filter); err == nil {
+			senderIDs = append(senderIDs, id)
+			continue
+		}
+		ids, err := s.messageRepo.FindUserIDsByName(strings.TrimPrefix(filter, "@"))
+		if err != nil {
+			return nil, err
+		}
+		senderIDs = append(senderIDs, ids...)
+	}
+	return uniqueObjectIDs(senderIDs), nil
+}
+
+func toSearchDocument(message *models.Message) search.Document {
+	return search.Document{
+		ID:        message.ID,
+		RoomID:    message.RoomID,
+		SenderID:  message.SenderID,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+
+		HasAttachment: len(message.Attachments) > 0,
+	}
+}
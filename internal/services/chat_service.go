@@ -4,33 +4,295 @@ import (
 	"chat-go-api/internal/common"
 	"chat-go-api/internal/models"
 	"chat-go-api/internal/repository"
+	"chat-go-api/internal/search"
+	"chat-go-api/internal/storage"
 	"chat-go-api/internal/utils"
+	"errors"
+	"log"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+var (
+	ErrRoomNotFound          = errors.New("chat room not found")
+	ErrRoomAccessDenied      = errors.New("you are not a member of this chat room")
+	ErrInvalidDirectPeer     = errors.New("cannot start a direct conversation with this user")
+	ErrInvalidRoomVisibility = errors.New("invalid room visibility")
+	ErrInvalidRoomListFilter = errors.New("invalid room list filter")
+)
+
+const (
+	defaultRoomListLimit  = 30
+	maxRoomListLimit      = 100
+	lastMessageSnippetLen = 100 // 채팅방 목록 미리보기 최대 글자 수
+)
+
+// RoomListQuery 채팅방 목록 조회 조건
+type RoomListQuery struct {
+	Type     string // group, direct (비어 있으면 전체)
+	Archived string // exclude(기본값), include, only
+	Cursor   string // 이전 응답의 next_cursor
+	Limit    int64
+}
+
+// ChatOptions 채팅 서비스 설정
+type ChatOptions struct {
+	EditWindow time.Duration // 메시지 수정 가능 시간, 0이면 제한 없음
+	// 삭제된 메시지의 원래 내용 보관 기간, 0이면 삭제 즉시 지움
+	DeletedMessageRetention time.Duration
+	MaxUploadSize           int64         // 첨부파일 최대 크기 (바이트)
+	DownloadURLTTL          time.Duration // 첨부파일 다운로드 URL 유효 시간
+	DownloadSigningKey      []byte        // 다운로드 URL 서명 키
+	// 메시지에 연결되지 않은 업로드 보관 기간, 0이면 정리하지 않음
+	UnclaimedAttachmentTTL time.Duration
+}
+
 type ChatService struct {
-	chatRepo    *repository.ChatRepository
-	messageRepo *repository.MessageRepository
+	chatRepo       *repository.ChatRepository
+	messageRepo    *repository.MessageRepository
+	attachmentRepo *repository.AttachmentRepository
+	manager        WebSocketManager
+	searcher       search.Searcher
+	blobStore      storage.Storage
+	imageService   *ImageService
+	options        ChatOptions
 }
 
-func NewChatService(chatRepo *repository.ChatRepository, messageRepo *repository.MessageRepository) *ChatService {
-	return &ChatService{chatRepo: chatRepo, messageRepo: messageRepo}
+func NewChatService(
+	chatRepo *repository.ChatRepository,
+	messageRepo *repository.MessageRepository,
+	attachmentRepo *repository.AttachmentRepository,
+	manager WebSocketManager,
+	searcher search.Searcher,
+	blobStore storage.Storage,
+	imageService *ImageService,
+	options ChatOptions,
+) *ChatService {
+	service := &ChatService{
+		chatRepo:       chatRepo,
+		messageRepo:    messageRepo,
+		attachmentRepo: attachmentRepo,
+		manager:        manager,
+		searcher:       searcher,
+		blobStore:      blobStore,
+		imageService:   imageService,
+		options:        options,
+	}
+	imageService.OnProcessed(service.attachmentProcessed)
+	return service
 }
 
-func (s *ChatService) CreateChatRoom(name string, memberIDs []primitive.ObjectID) (*models.ChatRoom, error) {
+// CreateChatRoom 채팅방 생성 (생성자가 방장이 되며 자동으로 멤버에 포함)
+func (s *ChatService) CreateChatRoom(creatorID string, name string, visibility string, memberIDs []primitive.ObjectID) (*models.ChatRoom, error) {
+	creatorObjectID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, err
+	}
+	if visibility == "" {
+		visibility = models.RoomVisibilityPrivate
+	}
+	if !models.IsValidRoomVisibility(visibility) {
+		return nil, ErrInvalidRoomVisibility
+	}
+
+	now := time.Now().Unix()
 	room := &models.ChatRoom{
-		Name:      name,
-		Members:   memberIDs,
-		CreatedAt: time.Now().Unix(),
+		Type:       models.RoomTypeGroup,
+		Name:       name,
+		Visibility: visibility,
+		OwnerID:    creatorObjectID,
+		Admins:     []primitive.ObjectID{},
+		Members:    uniqueObjectIDs(append([]primitive.ObjectID{creatorObjectID}, memberIDs...)),
+		CreatedAt:  now,
+
+		LastActivityAt: now,
 	}
-	err := s.chatRepo.CreateChatRoom(room)
+	err = s.chatRepo.CreateChatRoom(room)
 	return room, err
 }
 
+// AuthorizeRoomAccess 채팅방 접근 권한 확인
+// 채팅방 멤버만 메시지 조회, 입장, 전송이 가능하다.
+func (s *ChatService) AuthorizeRoomAccess(roomID, userID string) (*models.ChatRoom, error) {
+	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
+	if err != nil {
+		return nil, ErrRoomNotFound
+	}
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrRoomAccessDenied
+	}
+
+	room, err := s.chatRepo.GetChatRoomByID(roomObjectID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !containsObjectID(room.Members, userObjectID) {
+		return nil, ErrRoomAccessDenied
+	}
+	return room, nil
+}
+
+// ToChatRoomDTO 조회한 유저 기준으로 채팅방 DTO 변환
+func (s *ChatService) ToChatRoomDTO(room *models.ChatRoom, viewerID string) (*models.ChatRoomDTO, error) {
+	viewerObjectID, err := primitive.ObjectIDFromHex(viewerID)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ToChatRoomDTO(room, viewerObjectID, s.GetUserName)
+}
+
+// GetOrCreateDirectRoom 두 유저의 1:1 대화방을 조회하거나 생성
+func (s *ChatService) GetOrCreateDirectRoom(userID, peerID string) (*models.ChatRoomDTO, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	peerObjectID, err := primitive.ObjectIDFromHex(peerID)
+	if err != nil || peerObjectID == userObjectID {
+		return nil, ErrInvalidDirectPeer
+	}
+	if _, err := s.messageRepo.GetUserByID(peerObjectID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	now := time.Now().Unix()
+	room, err := s.chatRepo.GetOrCreateDirectRoom(&models.ChatRoom{
+		Type:           models.RoomTypeDirect,
+		DMKey:          directRoomKey(userObjectID, peerObjectID),
+		Admins:         []primitive.ObjectID{},
+		Members:        []primitive.ObjectID{userObjectID, peerObjectID},
+		CreatedAt:      now,
+		LastActivityAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return utils.ToChatRoomDTO(room, userObjectID, s.GetUserName)
+}
+
+// directRoomKey 참여자 순서와 무관한 1:1 대화방 키
+func directRoomKey(a, b primitive.ObjectID) string {
+	first, second := a.Hex(), b.Hex()
+	if first > second {
+		first, second = second, first
+	}
+	return first + ":" + second
+}
+
+// SaveMessage 메시지 저장 후 채팅방의 마지막 메시지 갱신
+// 모든 메시지 저장은 이 함수를 거쳐야 채팅방 목록 정렬과 미리보기가 맞게 유지된다.
 func (s *ChatService) SaveMessage(msg *models.Message) error {
-	return s.messageRepo.SaveMessage(msg)
+	_, err := s.saveMessage(msg)
+	return err
+}
+
+// PostMessage 메시지를 저장하고 채팅방에 브로드캐스트
+// 스레드 답글이면 부모 메시지의 답글 요약도 thread.updated 이벤트로 전달한다.
+func (s *ChatService) PostMessage(msg *models.Message) (*models.MessageDTO, error) {
+	parent, err := s.saveMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	messageDTO, err := utils.ToMessageDTO(msg, s.GetUserName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.manager.BroadcastToRoom(msg.RoomID.Hex(), messageDTO); err != nil {
+		log.Printf("Failed to broadcast message: %v", err)
+	}
+	if parent != nil {
+		s.publishThreadUpdate(parent)
+	}
+	return messageDTO, nil
+}
+
+// saveMessage 메시지 저장 (스레드 답글이면 갱신된 부모 메시지 반환)
+// msg.Attachments에 ID만 채워 전달하면 첨부파일을 메시지에 연결하고 나머지 정보를 채운다.
+func (s *ChatService) saveMessage(msg *models.Message) (*models.Message, error) {
+	if msg.IsReply() {
+		if err := s.validateThreadParent(msg); err != nil {
+			return nil, err
+		}
+	}
+	if len(msg.Attachments) > 0 {
+		if err := s.claimAttachments(msg); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.messageRepo.SaveMessage(msg); err != nil {
+		if len(msg.Attachments) > 0 {
+			if releaseErr := s.attachmentRepo.ReleaseAttachments(msg.ID); releaseErr != nil {
+				log.Printf("Failed to release attachments of message %s: %v", msg.ID.Hex(), releaseErr)
+			}
+		}
+		return nil, err
+	}
+	s.syncProcessingAttachments(msg)
+
+	messageType := msg.Type
+	if messageType == "" {
+		messageType = models.MessageTypeText
+	}
+	lastMessage := &models.RoomLastMessage{
+		ID:         msg.ID,
+		SenderID:   msg.SenderID,
+		SenderName: s.userName(msg.SenderID),
+		Type:       messageType,
+		Snippet:    messageSnippet(msg),
+		CreatedAt:  msg.CreatedAt,
+	}
+	// 미리보기 갱신에 실패해도 메시지는 이미 저장되었으므로 성공으로 처리
+	if err := s.chatRepo.UpdateLastMessage(msg.RoomID, lastMessage); err != nil {
+		log.Printf("Failed to update last message of room %s: %v", msg.RoomID.Hex(), err)
+	}
+
+	s.indexMessage(msg)
+	s.deliverMentions(msg, nil)
+
+	if !msg.IsReply() {
+		return nil, nil
+	}
+	parent, err := s.messageRepo.AddThreadReply(*msg.ParentID, msg.SenderID, msg.CreatedAt)
+	if err != nil {
+		log.Printf("Failed to update thread %s: %v", msg.ParentID.Hex(), err)
+		return nil, nil
+	}
+	return parent, nil
+}
+
+// 미리보기용으로 메시지 내용을 한 줄로 줄임 (내용 없이 첨부파일만 있으면 파일 이름)
+func messageSnippet(msg *models.Message) string {
+	content := msg.Content
+	if strings.TrimSpace(content) == "" && len(msg.Attachments) > 0 {
+		content = "📎 " + msg.Attachments[0].FileName
+	}
+	snippet := []rune(strings.Join(strings.Fields(content), " "))
+	if len(snippet) <= lastMessageSnippetLen {
+		return string(snippet)
+	}
+	return string(snippet[:lastMessageSnippetLen]) + "…"
+}
+
+// postSystemMessage 시스템 메시지를 저장하고 채팅방에 브로드캐스트
+func (s *ChatService) postSystemMessage(roomID, actorID primitive.ObjectID, content string) error {
+	message := &models.Message{
+		RoomID:    roomID,
+		SenderID:  actorID,
+		Type:      models.MessageTypeSystem,
+		Content:   content,
+		CreatedAt: time.Now().Unix(),
+	}
+	_, err := s.PostMessage(message)
+	return err
 }
 
 // GetChatRoomMessages 특정 채팅방의 메시지 로드
@@ -38,13 +300,86 @@ func (s *ChatService) GetChatRoomMessages(roomID primitive.ObjectID) ([]models.M
 	return s.messageRepo.GetMessagesByRoomID(roomID)
 }
 
-// GetUserChatRooms 유저가 참여한 채팅방 목록 조회
-func (s *ChatService) GetUserChatRooms(userID string) ([]models.ChatRoom, error) {
+// GetAllUserChatRooms 유저가 참여한 채팅방 전체 목록 조회 (페이지네이션 이전 클라이언트용)
+// 최대 페이지 크기로 마지막 페이지까지 이어서 조회한다.
+func (s *ChatService) GetAllUserChatRooms(userID string, query RoomListQuery) ([]*models.ChatRoomDTO, error) {
+	query.Cursor = ""
+	query.Limit = maxRoomListLimit
+
+	roomDTOs := []*models.ChatRoomDTO{}
+	for {
+		page, nextCursor, err := s.GetUserChatRooms(userID, query)
+		if err != nil {
+			return nil, err
+		}
+		roomDTOs = append(roomDTOs, page...)
+		if nextCursor == "" {
+			return roomDTOs, nil
+		}
+		query.Cursor = nextCursor
+	}
+}
+
+// GetUserChatRooms 유저가 참여한 채팅방 목록 조회 (최근 활동순, 커서 페이지네이션)
+// 다음 페이지가 있으면 next cursor를 함께 반환한다.
+func (s *ChatService) GetUserChatRooms(userID string, query RoomListQuery) ([]*models.ChatRoomDTO, string, error) {
 	uid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	opts := repository.RoomListOptions{Type: query.Type, Archived: query.Archived, Limit: query.Limit}
+	switch opts.Type {
+	case "", models.RoomTypeGroup, models.RoomTypeDirect:
+	default:
+		return nil, "", ErrInvalidRoomListFilter
+	}
+	switch opts.Archived {
+	case "":
+		opts.Archived = repository.ArchivedExclude
+	case repository.ArchivedExclude, repository.ArchivedInclude, repository.ArchivedOnly:
+	default:
+		return nil, "", ErrInvalidRoomListFilter
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultRoomListLimit
+	}
+	if opts.Limit > maxRoomListLimit {
+		opts.Limit = maxRoomListLimit
+	}
+	if query.Cursor != "" {
+		if opts.BeforeActivityAt, opts.BeforeID, err = utils.DecodeCursor(query.Cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// 다음 페이지 존재 여부 확인을 위해 하나 더 조회
+	limit := opts.Limit
+	opts.Limit++
+	rooms, err := s.chatRepo.GetChatRoomsByUserID(uid, opts)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(rooms)) > limit {
+		rooms = rooms[:limit]
+		last := rooms[len(rooms)-1]
+		nextCursor = utils.EncodeCursor(last.LastActivityAt, last.ID)
+	}
+
+	roomDTOs := []*models.ChatRoomDTO{}
+	for i := range rooms {
+		dto, err := utils.ToChatRoomDTO(&rooms[i], uid, s.GetUserName)
+		if err != nil {
+			return nil, "", err
+		}
+		roomDTOs = append(roomDTOs, dto)
+	}
+	if err := s.fillUnreadCounts(uid, roomDTOs); err != nil {
+		return nil, "", err
 	}
-	return s.chatRepo.GetChatRoomsByUserID(uid)
+	return roomDTOs, nextCursor, nil
 }
 
 func (s *ChatService) GetUserName(userID primitive.ObjectID) (string, error) {
@@ -56,15 +391,16 @@ func (s *ChatService) GetUserName(userID primitive.ObjectID) (string, error) {
 }
 
 // GetTotalMessagesCount 특정 채팅방의 메시지 총 개수
-func (s *ChatService) GetTotalMessagesCount(roomID string) (int64, error) {
+func (s *ChatService) GetTotalMessagesCount(roomID string, hideReplies bool) (int64, error) {
 	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
 	if err != nil {
 		return 0, err
 	}
-	return s.messageRepo.GetTotalMessagesCount(roomObjectID)
+	return s.messageRepo.GetTotalMessagesCount(roomObjectID, hideReplies)
 }
 
-func (s *ChatService) GetRecentMessages(roomID string, limit int64, page int64) ([]*models.MessageDTO, error) {
+// GetRecentMessages 채팅방 타임라인 조회 (hideReplies면 스레드 답글 제외)
+func (s *ChatService) GetRecentMessages(roomID string, limit int64, page int64, hideReplies bool) ([]*models.MessageDTO, error) {
 	// RoomID를 ObjectID로 변환
 	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
 	if err != nil {
@@ -72,7 +408,7 @@ func (s *ChatService) GetRecentMessages(roomID string, limit int64, page int64)
 	}
 
 	// 메시지 조회
-	messages, err := s.messageRepo.GetMessagesByRoomIDWithPagination(roomObjectID, limit, page)
+	messages, err := s.messageRepo.GetMessagesByRoomIDWithPagination(roomObjectID, limit, page, hideReplies)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/utils"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrMessageNotFound     = errors.New("message not found")
+	ErrInvalidThreadParent = errors.New("cannot reply to this message")
+)
+
+// ThreadPage 스레드 부모 메시지와 답글 페이지
+type ThreadPage struct {
+	Parent  *models.MessageDTO   `json:"parent"`
+	Replies []*models.MessageDTO `json:"replies"`
+	Total   int64                `json:"total"`
+	Page    int64                `json:"page"`
+	Limit   int64                `json:"limit"`
+}
+
+// GetThreadReplies 스레드 답글 조회 (채팅방 멤버만)
+func (s *ChatService) GetThreadReplies(roomID, userID, messageID string, limit, page int64) (*ThreadPage, error) {
+	room, err := s.AuthorizeRoomAccess(roomID, userID)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := s.getRoomMessage(room.ID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.IsReply() {
+		return nil, ErrInvalidThreadParent
+	}
+
+	replies, err := s.messageRepo.GetThreadReplies(parent.ID, limit, page)
+	if err != nil {
+		return nil, err
+	}
+
+	parentDTO, err := utils.ToMessageDTO(parent, s.GetUserName)
+	if err != nil {
+		return nil, err
+	}
+	replyDTOs := []*models.MessageDTO{}
+	for _, reply := range replies {
+		dto, err := utils.ToMessageDTO(reply, s.GetUserName)
+		if err != nil {
+			return nil, err
+		}
+		replyDTOs = append(replyDTOs, dto)
+	}
+
+	return &ThreadPage{
+		Parent:  parentDTO,
+		Replies: replyDTOs,
+		Total:   parent.ReplyCount,
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// 채팅방에 속한 메시지 조회 (다른 채팅방의 메시지는 찾을 수 없음으로 처리)
+func (s *ChatService) getRoomMessage(roomID primitive.ObjectID, messageID string) (*models.Message, error) {
+	messageObjectID, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return nil, ErrMessageNotFound
+	}
+	message, err := s.messageRepo.GetMessageByID(messageObjectID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if message.RoomID != roomID {
+		return nil, ErrMessageNotFound
+	}
+	return message, nil
+}
+
+// 답글의 부모 메시지 확인
+// 같은 채팅방의 삭제되지 않은 일반 메시지에만 답글을 달 수 있으며, 답글에 대한 답글은 허용하지 않는다.
+func (s *ChatService) validateThreadParent(reply *models.Message) error {
+	parent, err := s.getRoomMessage(reply.RoomID, reply.ParentID.Hex())
+	if errors.Is(err, ErrMessageNotFound) {
+		return ErrInvalidThreadParent
+	}
+	if err != nil {
+		return err
+	}
+	if parent.IsReply() || parent.IsDeleted() || parent.Type == models.MessageTypeSystem {
+		return ErrInvalidThreadParent
+	}
+	return nil
+}
+
+// 스레드 요약 변경 이벤트 브로드캐스트
+func (s *ChatService) publishThreadUpdate(parent *models.Message) {
+	s.publishEvent(parent.RoomID, models.EventThreadUpdated, map[string]interface{}{
+		"parent_id":     parent.ID,
+		"reply_count":   parent.ReplyCount,
+		"last_reply_at": parent.LastReplyAt,
+		"participants":  parent.ThreadParticipants,
+	})
+}
@@ -5,29 +5,40 @@ import (
 	"fmt"
 	"log"
 	"net/smtp"
+	"net/url"
+	"strings"
 	"time"
 )
 
+// 이메일 종류
+const (
+	EmailTypeVerification  = "verification"
+	EmailTypePasswordReset = "password_reset"
+)
+
 type EmailTask struct {
+	Type  string
 	To    string
 	Token string
 }
 
 type EmailService struct {
-	smtpHost string
-	smtpPort string
-	username string
-	password string
-	tasks    chan EmailTask // 작업 큐
+	smtpHost    string
+	smtpPort    string
+	username    string
+	password    string
+	frontendURL string         // 비밀번호 재설정 페이지를 제공하는 프론트엔드 주소
+	tasks       chan EmailTask // 작업 큐
 }
 
-func NewEmailService(smtpHost, smtpPort, username, password string, queueSize int, numWorkers int) *EmailService {
+func NewEmailService(smtpHost, smtpPort, username, password, frontendURL string, queueSize int, numWorkers int) *EmailService {
 	service := &EmailService{
-		smtpHost: smtpHost,
-		smtpPort: smtpPort,
-		username: username,
-		password: password,
-		tasks:    make(chan EmailTask, queueSize), // 큐 생성
+		smtpHost:    smtpHost,
+		smtpPort:    smtpPort,
+		username:    username,
+		password:    password,
+		frontendURL: strings.TrimRight(frontendURL, "/"),
+		tasks:       make(chan EmailTask, queueSize), // 큐 생성
 	}
 
 	// 워커 고루틴 실행
@@ -41,7 +52,7 @@ func NewEmailService(smtpHost, smtpPort, username, password string, queueSize in
 // 워커 실행: 큐에서 작업을 처리
 func (s *EmailService) startWorker() {
 	for task := range s.tasks {
-		err := s.sendEmail(task.To, task.Token)
+		err := s.sendEmail(task)
 		if err != nil {
 			log.Printf("Failed to send email to %s: %v", task.To, err)
 		} else {
@@ -53,27 +64,54 @@ func (s *EmailService) startWorker() {
 var ErrEmailQueueFull = errors.New("email queue is full or timed out")
 
 func (s *EmailService) SendVerificationEmailAsync(to, token string) error {
+	return s.enqueue(EmailTask{Type: EmailTypeVerification, To: to, Token: token})
+}
+
+func (s *EmailService) SendPasswordResetEmailAsync(to, token string) error {
+	return s.enqueue(EmailTask{Type: EmailTypePasswordReset, To: to, Token: token})
+}
+
+// 작업 큐에 이메일 추가
+func (s *EmailService) enqueue(task EmailTask) error {
 	select {
-	case s.tasks <- EmailTask{To: to, Token: token}:
-		log.Printf("Email task added to queue for %s", to)
+	case s.tasks <- task:
+		log.Printf("Email task added to queue for %s", task.To)
 		return nil
 	case <-time.After(1 * time.Second): // 작업 추가 제한 시간
-		log.Printf("Failed to add email task for %s: queue timeout", to)
+		log.Printf("Failed to add email task for %s: queue timeout", task.To)
 		return ErrEmailQueueFull
 	}
 }
 
+// 이메일 종류별 제목과 본문 작성
+func (s *EmailService) composeEmail(task EmailTask) (string, string, error) {
+	switch task.Type {
+	case EmailTypeVerification:
+		link := fmt.Sprintf("http://localhost:8080/verify-email?token=%s", task.Token)
+		return "Email Verification", fmt.Sprintf("Click the link to verify your email: %s", link), nil
+	case EmailTypePasswordReset:
+		// 재설정 폼은 프론트엔드가 제공하고, 폼에서 POST /password/reset으로 토큰과 새 비밀번호를 보낸다.
+		link := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, url.QueryEscape(task.Token))
+		body := fmt.Sprintf("Click the link to reset your password: %s\r\nIf you did not request a password reset, you can ignore this email.", link)
+		return "Password Reset", body, nil
+	default:
+		return "", "", fmt.Errorf("unknown email type: %s", task.Type)
+	}
+}
+
 // 동기 이메일 전송: 실제 이메일 전송 처리
-func (s *EmailService) sendEmail(to, token string) error {
+func (s *EmailService) sendEmail(task EmailTask) error {
 	from := s.username
 	auth := smtp.PlainAuth("", s.username, s.password, s.smtpHost)
 
-	link := fmt.Sprintf("http://localhost:8080/verify-email?token=%s", token)
-	body := fmt.Sprintf("Click the link to verify your email: %s", link)
-	message := []byte("Subject: Email Verification\r\n\r\n" + body)
+	subject, body, err := s.composeEmail(task)
+	if err != nil {
+		return err
+	}
+	message := []byte("Subject: " + subject + "\r\n\r\n" + body)
 
 	time.Sleep(2 * time.Second) // 이메일 전송 지연 시뮬레이션 (테스트용)
-	err := smtp.SendMail(s.smtpHost+":"+s.smtpPort, auth, from, []string{to}, message)
+	err = smtp.SendMail(s.smtpHost+":"+s.smtpPort, auth, from, []string{task.To}, message)
 	if err != nil {
 		return fmt.Errorf("failed to send email: %v", err)
 	}
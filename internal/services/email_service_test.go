@@ -0,0 +1,18 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposePasswordResetEmail(t *testing.T) {
+	service := &EmailService{frontendURL: "https://chat.example.com"}
+
+	_, body, err := service.composeEmail(EmailTask{Type: EmailTypePasswordReset, To: "user@example.com", Token: "abc123"})
+	if err != nil {
+		t.Fatalf("composeEmail() error = %v", err)
+	}
+	if !strings.Contains(body, "https://chat.example.com/reset-password?token=abc123") {
+		t.Errorf("composeEmail() body = %q, want link to the frontend reset page", body)
+	}
+}
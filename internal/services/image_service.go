@@ -0,0 +1,244 @@
+package services
+
+import (
+	"bytes"
+	"chat-go-api/internal/imaging"
+	"chat-go-api/internal/models"
+	"chat-go-api/internal/repository"
+	"chat-go-api/internal/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 생성할 썸네일 (긴 변 기준 최대 크기)
+var thumbnailSpecs = []imaging.VariantSpec{
+	{Name: "small", MaxSize: 160},
+	{Name: "medium", MaxSize: 480},
+	{Name: "large", MaxSize: 1280},
+}
+
+const (
+	maxImageAttempts = 4               // 저장소 오류 시 최대 처리 횟수
+	imageRetryDelay  = 2 * time.Second // 첫 재시도 대기 시간 (시도마다 두 배)
+)
+
+var ErrImageQueueFull = errors.New("image processing queue is full")
+
+type ImageTask struct {
+	AttachmentID primitive.ObjectID
+	Attempt      int // 이미 실패한 횟수
+}
+
+// ImageService 이미지 첨부파일 처리 (메타데이터 제거, 썸네일과 블러 미리보기 생성)
+type ImageService struct {
+	attachmentRepo *repository.AttachmentRepository
+	blobStore      storage.Storage
+	tasks          chan ImageTask // 작업 큐
+	onProcessed    func(attachment *models.Attachment)
+}
+
+func NewImageService(attachmentRepo *repository.AttachmentRepository, blobStore storage.Storage, queueSize int, numWorkers int) *ImageService {
+	service := &ImageService{
+		attachmentRepo: attachmentRepo,
+		blobStore:      blobStore,
+		tasks:          make(chan ImageTask, queueSize), // 큐 생성
+	}
+
+	// 워커 고루틴 실행
+	for i := 0; i < numWorkers; i++ {
+		go service.startWorker()
+	}
+
+	return service
+}
+
+// OnProcessed 처리가 끝난 첨부파일을 받을 함수 등록 (작업을 추가하기 전에 호출)
+func (s *ImageService) OnProcessed(handler func(attachment *models.Attachment)) {
+	s.onProcessed = handler
+}
+
+// 워커 실행: 큐에서 작업을 처리
+func (s *ImageService) startWorker() {
+	for task := range s.tasks {
+		if err := s.processImage(task); err != nil {
+			s.retry(task, err)
+		}
+	}
+}
+
+// 저장소나 DB 오류로 실패한 작업을 잠시 뒤 다시 큐에 추가
+// 재시도 횟수를 넘기면 처리 실패로 표시해 첨부파일이 처리 중 상태로 남지 않게 한다.
+func (s *ImageService) retry(task ImageTask, err error) {
+	task.Attempt++
+	if task.Attempt >= maxImageAttempts {
+		log.Printf("Failed to process image attachment %s after %d attempts: %v", task.AttachmentID.Hex(), task.Attempt, err)
+		s.markFailed(task.AttachmentID)
+		return
+	}
+
+	delay := imageRetryDelay << (task.Attempt - 1)
+	log.Printf("Failed to process image attachment %s, retrying in %s: %v", task.AttachmentID.Hex(), delay, err)
+	time.AfterFunc(delay, func() {
+		s.tasks <- task
+	})
+}
+
+func (s *ImageService) markFailed(attachmentID primitive.ObjectID) {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(attachmentID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			log.Printf("Failed to mark image attachment %s as failed: %v", attachmentID.Hex(), err)
+		}
+		return
+	}
+	if attachment.Status != models.AttachmentStatusProcessing {
+		return
+	}
+	attachment.Status = models.AttachmentStatusFailed
+	attachment.Variants = nil
+	if _, err := s.finish(attachment); err != nil {
+		log.Printf("Failed to mark image attachment %s as failed: %v", attachmentID.Hex(), err)
+	}
+}
+
+// ProcessImageAsync 이미지 첨부파일 처리 작업을 큐에 추가
+func (s *ImageService) ProcessImageAsync(attachmentID primitive.ObjectID) error {
+	select {
+	case s.tasks <- ImageTask{AttachmentID: attachmentID}:
+		return nil
+	case <-time.After(1 * time.Second): // 작업 추가 제한 시간
+		log.Printf("Failed to add image task for %s: queue timeout", attachmentID.Hex())
+		return ErrImageQueueFull
+	}
+}
+
+// ResumePending 서버가 중단되어 처리되지 못한 이미지 첨부파일을 다시 큐에 추가
+func (s *ImageService) ResumePending() error {
+	attachments, err := s.attachmentRepo.GetProcessingAttachments()
+	if err != nil {
+		return err
+	}
+	go func() {
+		for _, attachment := range attachments {
+			s.tasks <- ImageTask{AttachmentID: attachment.ID}
+		}
+	}()
+	return nil
+}
+
+// 원본의 메타데이터를 제거해 교체하고 썸네일을 저장
+func (s *ImageService) processImage(task ImageTask) error {
+	attachment, err := s.attachmentRepo.GetAttachmentByID(task.AttachmentID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil // 처리 전에 삭제됨
+	}
+	if err != nil {
+		return err
+	}
+	if attachment.Status != models.AttachmentStatusProcessing {
+		return nil
+	}
+
+	original, err := s.readBlob(attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		// 원본이 없으면 다시 시도해도 처리할 수 없음
+		log.Printf("Original of image attachment %s is missing", attachment.ID.Hex())
+		attachment.Status = models.AttachmentStatusFailed
+		_, err := s.finish(attachment)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	result, err := imaging.Process(original, thumbnailSpecs)
+	if err != nil {
+		// 이미지 자체의 문제는 다시 시도해도 같으므로 바로 실패로 표시
+		log.Printf("Failed to process image attachment %s: %v", attachment.ID.Hex(), err)
+		attachment.Status = models.AttachmentStatusFailed
+		_, err := s.finish(attachment)
+		return err
+	}
+
+	ctx := context.TODO()
+	var stored []string
+	for _, variant := range result.Variants {
+		key := fmt.Sprintf("%s.%s", attachment.StorageKey, variant.Name)
+		if err := s.blobStore.Put(ctx, key, bytes.NewReader(variant.Data), int64(len(variant.Data)), variant.ContentType); err != nil {
+			s.deleteBlobs(stored)
+			return err
+		}
+		stored = append(stored, key)
+		attachment.Variants = append(attachment.Variants, models.AttachmentVariant{
+			Name:        variant.Name,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        int64(len(variant.Data)),
+			ContentType: variant.ContentType,
+			StorageKey:  key,
+		})
+	}
+	if !bytes.Equal(result.Original, original) {
+		if err := s.blobStore.Put(ctx, attachment.StorageKey, bytes.NewReader(result.Original), int64(len(result.Original)), result.ContentType); err != nil {
+			s.deleteBlobs(stored)
+			return err
+		}
+		checksum := sha256.Sum256(result.Original)
+		attachment.Size = int64(len(result.Original))
+		attachment.SHA256 = hex.EncodeToString(checksum[:])
+	}
+
+	attachment.Status = models.AttachmentStatusReady
+	attachment.Width = result.Width
+	attachment.Height = result.Height
+	attachment.Placeholder = result.Placeholder
+	saved, err := s.finish(attachment)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		// 처리 중에 삭제된 첨부파일은 다시 저장한 원본까지 정리
+		s.deleteBlobs(append(stored, attachment.StorageKey))
+	}
+	return nil
+}
+
+// 처리 결과를 저장하고 알림 (처리 중에 첨부파일이 삭제되었으면 false)
+func (s *ImageService) finish(attachment *models.Attachment) (bool, error) {
+	updated, err := s.attachmentRepo.UpdateProcessedAttachment(attachment)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	if s.onProcessed != nil {
+		s.onProcessed(updated)
+	}
+	return true, nil
+}
+
+func (s *ImageService) readBlob(key string) ([]byte, error) {
+	body, err := s.blobStore.Get(context.TODO(), key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+func (s *ImageService) deleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.blobStore.Delete(context.TODO(), key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/pkg/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	totpIssuer           = "Chat Go"
+	totpSkew             = 1 // 앞뒤 1스텝(30초)까지 허용
+	challengeTokenTTL    = 5 * time.Minute
+	maxChallengeAttempts = 5 // 챌린지 토큰 하나로 코드를 시도할 수 있는 횟수
+	recoveryCodeCount    = 10
+	tokenTypeChallenge   = "2fa_challenge"
+	recoveryCodeByteLen  = 5
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolling   = errors.New("two-factor enrollment has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrInvalidChallengeToken   = errors.New("invalid or expired challenge token")
+)
+
+// TwoFactorEnrollment 2단계 인증 등록 정보
+type TwoFactorEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// 2단계 인증 등록 시작: 시크릿을 발급하고 확인 전까지 대기 상태로 저장
+func (s *AuthService) BeginTwoFactorEnrollment(userID string) (*TwoFactorEnrollment, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetTOTPPendingSecret(user.ID, secret); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// 2단계 인증 등록 확인: 첫 코드를 검증한 뒤 활성화하고 복구 코드를 반환
+func (s *AuthService) ConfirmTwoFactorEnrollment(userID, code string) ([]string, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if user.TOTPPendingSecret == "" {
+		return nil, ErrTwoFactorNotEnrolling
+	}
+
+	step, ok := utils.ValidateTOTP(user.TOTPPendingSecret, code, s.now(), totpSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.EnableTOTP(user.ID, user.TOTPPendingSecret, step, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// 2단계 인증 해제 (비밀번호와 코드 모두 필요)
+func (s *AuthService) DisableTwoFactor(userID, password, code string) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("invalid password")
+	}
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return err
+	}
+
+	return s.repo.DisableTOTP(user.ID)
+}
+
+// 2단계 로그인: 챌린지 토큰과 코드(TOTP 또는 복구 코드)를 검증하고 세션 생성
+// 챌린지 토큰은 한 번만 로그인에 사용할 수 있고, 코드를 여러 번 틀리면 더 이상 사용할 수 없다.
+func (s *AuthService) VerifyTwoFactorLogin(challengeToken, code, userAgent, ipAddress string) (*LoginResult, error) {
+	claims, err := s.parseToken(challengeToken, s.accessSecret, tokenTypeChallenge)
+	if err != nil {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	user, err := s.getUser(claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidChallengeToken
+	}
+	if !user.TOTPEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	// 코드 검증 전에 시도 횟수를 먼저 늘려 동시 요청으로 제한을 우회할 수 없게 함
+	challenge, err := s.repo.AddTwoFactorChallengeAttempt(claims.FamilyID, user.ID, s.now().Add(challengeTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+	if challenge.ConsumedAt != 0 || challenge.Attempts > maxChallengeAttempts {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return nil, err
+	}
+
+	consumed, err := s.repo.ConsumeTwoFactorChallenge(claims.FamilyID, s.now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidChallengeToken
+	}
+
+	return s.createSession(user, userAgent, ipAddress)
+}
+
+// TOTP 코드 또는 복구 코드 검증
+// 같은 타임 스텝의 코드와 이미 사용한 복구 코드는 다시 사용할 수 없다.
+func (s *AuthService) verifySecondFactor(user *models.User, code string) error {
+	if step, ok := utils.ValidateTOTP(user.TOTPSecret, code, s.now(), totpSkew); ok {
+		updated, err := s.repo.UpdateTOTPLastStep(user.ID, step)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	consumed, err := s.repo.ConsumeRecoveryCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// 2단계 로그인용 챌린지 토큰 생성
+func (s *AuthService) generateChallengeToken(userID string) (string, error) {
+	challengeID, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	return s.generateToken(userID, challengeID, "", tokenTypeChallenge, s.accessSecret, challengeTokenTTL)
+}
+
+func (s *AuthService) getUser(userID string) (*models.User, error) {
+	id, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserByID(id)
+}
+
+// 복구 코드 생성 (평문 코드와 저장용 해시)
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw, err := randomHex(recoveryCodeByteLen)
+		if err != nil {
+			return nil, nil, err
+		}
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// 복구 코드 해시 (대소문자, 공백, 하이픈 무시)
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"chat-go-api/internal/models"
+	"chat-go-api/pkg/utils"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserStore 2단계 인증 테스트용 메모리 저장소
+// 테스트에서 사용하지 않는 메서드는 내장된 nil 인터페이스로 남겨둔다.
+type fakeUserStore struct {
+	UserStore
+	users      map[primitive.ObjectID]*models.User
+	challenges map[string]*models.TwoFactorChallenge
+	histories  []*models.LoginHistory
+}
+
+func newFakeUserStore() *fakeUserStore {
+	return &fakeUserStore{
+		users:      map[primitive.ObjectID]*models.User{},
+		challenges: map[string]*models.TwoFactorChallenge{},
+	}
+}
+
+func (f *fakeUserStore) FindByEmail(email string) (*models.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeUserStore) GetUserByID(userID primitive.ObjectID) (*models.User, error) {
+	user, ok := f.users[userID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (f *fakeUserStore) AddLoginHistory(history *models.LoginHistory) error {
+	f.histories = append(f.histories, history)
+	return nil
+}
+
+func (f *fakeUserStore) SetTOTPPendingSecret(userID primitive.ObjectID, secret string) error {
+	f.users[userID].TOTPPendingSecret = secret
+	return nil
+}
+
+func (f *fakeUserStore) EnableTOTP(userID primitive.ObjectID, secret string, step int64, recoveryCodes []string) error {
+	user := f.users[userID]
+	user.TOTPEnabled = true
+	user.TOTPSecret = secret
+	user.TOTPLastStep = step
+	user.RecoveryCodes = recoveryCodes
+	user.TOTPPendingSecret = ""
+	return nil
+}
+
+func (f *fakeUserStore) UpdateTOTPLastStep(userID primitive.ObjectID, step int64) (bool, error) {
+	user := f.users[userID]
+	if user.TOTPLastStep >= step {
+		return false, nil
+	}
+	user.TOTPLastStep = step
+	return true, nil
+}
+
+func (f *fakeUserStore) ConsumeRecoveryCode(userID primitive.ObjectID, codeHash string) (bool, error) {
+	user := f.users[userID]
+	for i, hash := range user.RecoveryCodes {
+		if hash == codeHash {
+			user.RecoveryCodes = append(user.RecoveryCodes[:i], user.RecoveryCodes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserStore) AddTwoFactorChallengeAttempt(challengeID string, userID primitive.ObjectID, expiresAt time.Time) (*models.TwoFactorChallenge, error) {
+	challenge, ok := f.challenges[challengeID]
+	if !ok {
+		challenge = &models.TwoFactorChallenge{ChallengeID: challengeID, UserID: userID, ExpiresAt: expiresAt}
+		f.challenges[challengeID] = challenge
+	}
+	challenge.Attempts++
+	copied := *challenge
+	return &copied, nil
+}
+
+func (f *fakeUserStore) ConsumeTwoFactorChallenge(challengeID string, consumedAt int64) (bool, error) {
+	challenge, ok := f.challenges[challengeID]
+	if !ok || challenge.ConsumedAt != 0 {
+		return false, nil
+	}
+	challenge.ConsumedAt = consumedAt
+	return true, nil
+}
+
+const testPassword = "correct-horse"
+
+// 고정 시계를 사용하는 인증 서비스와 이메일 인증이 끝난 유저 생성
+func newTwoFactorTestService(t *testing.T) (*AuthService, *fakeUserStore, *models.User, *time.Time) {
+	t.Helper()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{
+		ID:              primitive.NewObjectID(),
+		Email:           "alice@example.com",
+		Password:        string(hashed),
+		IsEmailVerified: true,
+	}
+
+	store := newFakeUserStore()
+	store.users[user.ID] = user
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	service := NewAuthService(store, nil, nil, "access-secret", "refresh-secret")
+	service.now = func() time.Time { return now }
+	return service, store, user, &now
+}
+
+func currentCode(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	code, err := utils.TOTPCode(secret, utils.TOTPStep(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// 허용 범위 안의 어느 스텝과도 일치하지 않는 코드
+func wrongCode(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	for i := 0; i < 10; i++ {
+		code := strings.Repeat(strconv.Itoa(i), 6)
+		if _, ok := utils.ValidateTOTP(secret, code, now, totpSkew); !ok {
+			return code
+		}
+	}
+	t.Fatal("no wrong code found")
+	return ""
+}
+
+// 등록 → 확인 → 로그인까지 마치고 복구 코드를 반환
+func enrollTwoFactor(t *testing.T, service *AuthService, user *models.User, now *time.Time) (string, []string) {
+	t.Helper()
+
+	enrollment, err := service.BeginTwoFactorEnrollment(user.ID.Hex())
+	if err != nil {
+		t.Fatalf("BeginTwoFactorEnrollment: %v", err)
+	}
+	codes, err := service.ConfirmTwoFactorEnrollment(user.ID.Hex(), currentCode(t, enrollment.Secret, *now))
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactorEnrollment: %v", err)
+	}
+	return enrollment.Secret, codes
+}
+
+func loginChallenge(t *testing.T, service *AuthService) string {
+	t.Helper()
+
+	result, err := service.Login("alice@example.com", testPassword, "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if result.ChallengeToken == "" || result.AccessToken != "" {
+		t.Fatalf("Login should return only a challenge token, got %+v", result)
+	}
+	return result.ChallengeToken
+}
+
+func TestTwoFactorEnrollConfirmLogin(t *testing.T) {
+	service, store, user, now := newTwoFactorTestService(t)
+
+	secret, codes := enrollTwoFactor(t, service, user, now)
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	if !store.users[user.ID].TOTPEnabled {
+		t.Fatal("two-factor should be enabled after confirmation")
+	}
+
+	// 등록 확인에 사용한 코드는 같은 스텝에서 다시 사용할 수 없음
+	*now = now.Add(30 * time.Second)
+	challenge := loginChallenge(t, service)
+
+	result, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "test-agent", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("VerifyTwoFactorLogin: %v", err)
+	}
+	if result.AccessToken == "" || result.RefreshToken == "" {
+		t.Fatalf("expected a session, got %+v", result)
+	}
+	if len(store.histories) != 1 {
+		t.Fatalf("got %d login histories, want 1", len(store.histories))
+	}
+}
+
+func TestConfirmTwoFactorEnrollmentRejectsWrongCode(t *testing.T) {
+	service, store, user, _ := newTwoFactorTestService(t)
+
+	enrollment, err := service.BeginTwoFactorEnrollment(user.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ConfirmTwoFactorEnrollment(user.ID.Hex(), wrongCode(t, enrollment.Secret, service.now())); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("got %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if store.users[user.ID].TOTPEnabled {
+		t.Fatal("two-factor should stay disabled")
+	}
+}
+
+func TestTwoFactorRejectsReusedTOTPStep(t *testing.T) {
+	service, _, user, now := newTwoFactorTestService(t)
+	secret, _ := enrollTwoFactor(t, service, user, now)
+
+	// 등록 확인과 같은 스텝의 코드
+	challenge := loginChallenge(t, service)
+	if _, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "", ""); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("got %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorRecoveryCodeIsSingleUse(t *testing.T) {
+	service, store, user, now := newTwoFactorTestService(t)
+	_, codes := enrollTwoFactor(t, service, user, now)
+
+	if _, err := service.VerifyTwoFactorLogin(loginChallenge(t, service), codes[0], "", ""); err != nil {
+		t.Fatalf("first use of recovery code: %v", err)
+	}
+	if got := len(store.users[user.ID].RecoveryCodes); got != recoveryCodeCount-1 {
+		t.Fatalf("got %d remaining recovery codes, want %d", got, recoveryCodeCount-1)
+	}
+
+	if _, err := service.VerifyTwoFactorLogin(loginChallenge(t, service), codes[0], "", ""); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("second use: got %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
+
+func TestTwoFactorRejectsExpiredChallenge(t *testing.T) {
+	service, _, user, now := newTwoFactorTestService(t)
+	secret, _ := enrollTwoFactor(t, service, user, now)
+
+	challenge := loginChallenge(t, service)
+	*now = now.Add(challengeTokenTTL + time.Second)
+
+	if _, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "", ""); !errors.Is(err, ErrInvalidChallengeToken) {
+		t.Fatalf("got %v, want ErrInvalidChallengeToken", err)
+	}
+}
+
+func TestTwoFactorRejectsReusedChallenge(t *testing.T) {
+	service, _, user, now := newTwoFactorTestService(t)
+	secret, _ := enrollTwoFactor(t, service, user, now)
+
+	*now = now.Add(30 * time.Second)
+	challenge := loginChallenge(t, service)
+	if _, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "", ""); err != nil {
+		t.Fatalf("VerifyTwoFactorLogin: %v", err)
+	}
+
+	// 다음 스텝의 올바른 코드라도 이미 사용된 챌린지는 거부
+	*now = now.Add(30 * time.Second)
+	if _, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "", ""); !errors.Is(err, ErrInvalidChallengeToken) {
+		t.Fatalf("got %v, want ErrInvalidChallengeToken", err)
+	}
+}
+
+func TestTwoFactorChallengeLockedAfterFailedAttempts(t *testing.T) {
+	service, _, user, now := newTwoFactorTestService(t)
+	secret, _ := enrollTwoFactor(t, service, user, now)
+
+	*now = now.Add(30 * time.Second)
+	challenge := loginChallenge(t, service)
+	for i := 0; i < maxChallengeAttempts; i++ {
+		if _, err := service.VerifyTwoFactorLogin(challenge, wrongCode(t, secret, *now), "", ""); !errors.Is(err, ErrInvalidTwoFactorCode) {
+			t.Fatalf("attempt %d: got %v, want ErrInvalidTwoFactorCode", i+1, err)
+		}
+	}
+
+	if _, err := service.VerifyTwoFactorLogin(challenge, currentCode(t, secret, *now), "", ""); !errors.Is(err, ErrInvalidChallengeToken) {
+		t.Fatalf("got %v, want ErrInvalidChallengeToken", err)
+	}
+}
@@ -4,8 +4,8 @@ import (
 	"chat-go-api/internal/common"
 	"chat-go-api/internal/models"
 	"chat-go-api/internal/repository"
-	"chat-go-api/internal/utils"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,26 +13,40 @@ import (
 
 type WebSocketManager interface {
 	BroadcastToRoom(roomID string, message *models.MessageDTO) error
+	BroadcastEventToRoom(roomID string, event *models.RoomEvent) error
+	SendEventToUser(userID string, event *models.RoomEvent) bool
+	IsUserConnected(userID string) bool
+	DisconnectUserFromRoom(roomID, userID string)
+	CloseRoom(roomID string)
 }
 
 type WebSocketService struct {
 	manager      WebSocketManager
 	messageRepo  *repository.MessageRepository
 	chatRoomRepo *repository.ChatRepository
+	chatService  *ChatService
 }
 
 func NewWebSocketService(
 	manager WebSocketManager,
 	messageRepo *repository.MessageRepository,
 	chatRoomRepo *repository.ChatRepository,
+	chatService *ChatService,
 ) *WebSocketService {
 	return &WebSocketService{
 		manager:      manager,
 		messageRepo:  messageRepo,
 		chatRoomRepo: chatRoomRepo,
+		chatService:  chatService,
 	}
 }
 
+// AuthorizeJoin 채팅방 입장 권한 확인
+func (s *WebSocketService) AuthorizeJoin(roomID, userID string) error {
+	_, err := s.chatService.AuthorizeRoomAccess(roomID, userID)
+	return err
+}
+
 func (s *WebSocketService) GetUserName(userID primitive.ObjectID) (string, error) {
 	user, err := s.messageRepo.GetUserByID(userID)
 	if err != nil {
@@ -41,45 +55,104 @@ func (s *WebSocketService) GetUserName(userID primitive.ObjectID) (string, error
 	return user.Name, nil
 }
 
+// WebSocket 메시지 action 종류
+const (
+	ActionSend    = "send" // 기본값
+	ActionEdit    = "edit"
+	ActionDelete  = "delete"
+	ActionReact   = "react"
+	ActionUnreact = "unreact"
+	ActionRead    = "read"
+)
+
+var ErrUnknownAction = errors.New("unknown websocket action")
+
+// incomingMessage 클라이언트가 보내는 WebSocket 메시지
+type incomingMessage struct {
+	Action    string `json:"action,omitempty"`
+	Content   string `json:"content"`
+	ParentID  string `json:"parent_id,omitempty"`  // 스레드 답글이면 부모 메시지 ID
+	MessageID string `json:"message_id,omitempty"` // 수정/삭제/리액션/읽음 대상 메시지 ID
+	Emoji     string `json:"emoji,omitempty"`
+
+	AttachmentIDs []string `json:"attachment_ids,omitempty"` // 미리 업로드한 첨부파일 ID
+}
+
 func (s *WebSocketService) HandleIncomingMessage(roomID, senderID string, data []byte) error {
-	var msg struct {
-		Content string `json:"content"`
-	}
+	var msg incomingMessage
 
 	// 메시지 파싱
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return err
 	}
 
-	// 메시지 모델 생성
-	roomObjectID, err := primitive.ObjectIDFromHex(roomID)
+	switch msg.Action {
+	case "", ActionSend:
+		return s.handleSend(roomID, senderID, &msg)
+	case ActionEdit:
+		_, err := s.chatService.EditMessage(roomID, senderID, msg.MessageID, msg.Content)
+		return err
+	case ActionDelete:
+		_, err := s.chatService.DeleteMessage(roomID, senderID, msg.MessageID)
+		return err
+	case ActionReact:
+		_, err := s.chatService.AddReaction(roomID, senderID, msg.MessageID, msg.Emoji)
+		return err
+	case ActionUnreact:
+		_, err := s.chatService.RemoveReaction(roomID, senderID, msg.MessageID, msg.Emoji)
+		return err
+	case ActionRead:
+		_, err := s.chatService.MarkRoomRead(roomID, senderID, msg.MessageID)
+		return err
+	default:
+		return ErrUnknownAction
+	}
+}
+
+func (s *WebSocketService) handleSend(roomID, senderID string, msg *incomingMessage) error {
+	// 메시지마다 권한 확인 (접속 이후 멤버에서 제외되었을 수 있음)
+	room, err := s.chatService.AuthorizeRoomAccess(roomID, senderID)
 	if err != nil {
 		return err
 	}
+	// 보관된 채팅방은 읽기 전용
+	if room.IsArchived() {
+		return ErrRoomArchived
+	}
 
+	// 메시지 모델 생성
 	senderObjectID, err := primitive.ObjectIDFromHex(senderID)
 	if err != nil {
 		return err
 	}
 
 	message := &models.Message{
-		RoomID:    roomObjectID,
+		RoomID:    room.ID,
 		SenderID:  senderObjectID,
 		Content:   msg.Content,
 		CreatedAt: time.Now().Unix(),
 	}
-
-	// 메시지 저장
-	if err := s.messageRepo.SaveMessage(message); err != nil {
-		return err
+	if msg.ParentID != "" {
+		parentID, err := primitive.ObjectIDFromHex(msg.ParentID)
+		if err != nil {
+			return ErrInvalidThreadParent
+		}
+		message.ParentID = &parentID
+	}
+	for _, id := range msg.AttachmentIDs {
+		attachmentID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return ErrInvalidAttachment
+		}
+		message.Attachments = append(message.Attachments, models.MessageAttachment{ID: attachmentID})
 	}
 
-	// 메시지 DTO 생성
-	messageDTO, err := utils.ToMessageDTO(message, s.GetUserName)
-	if err != nil {
+	// @멘션을 채팅방 멤버로 확인
+	if err := s.chatService.ResolveMentions(room, message); err != nil {
 		return err
 	}
 
-	// 브로드캐스트
-	return s.manager.BroadcastToRoom(roomID, messageDTO)
+	// 메시지 저장 및 브로드캐스트
+	_, err = s.chatService.PostMessage(message)
+	return err
 }
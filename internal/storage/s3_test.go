@@ -0,0 +1,257 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "us-east-1"
+	testBucket    = "attachments"
+)
+
+var testNow = time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+
+// fakeS3 SigV4 서명을 검증하는 S3 대체 서버 (객체는 메모리에 저장)
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+	hosts   []string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	t.Helper()
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return fake, server
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := verifySigV4(r, testAccessKey, testSecretKey, testRegion); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.hosts = append(f.hosts, r.Host)
+	key := r.URL.Path
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		body, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// 받은 요청으로 정식 요청(canonical request)을 다시 만들어 Authorization 헤더의 서명과 비교
+func verifySigV4(r *http.Request, accessKey, secretKey, region string) error {
+	auth := r.Header.Get("Authorization")
+	const algorithm = "AWS4-HMAC-SHA256 "
+	if !strings.HasPrefix(auth, algorithm) {
+		return errors.New("missing AWS4-HMAC-SHA256 authorization")
+	}
+	fields := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, algorithm), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return errors.New("malformed authorization")
+		}
+		fields[name] = value
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	date, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return errors.New("invalid x-amz-date")
+	}
+	scope := date.Format("20060102") + "/" + region + "/s3/aws4_request"
+	if fields["Credential"] != accessKey+"/"+scope {
+		return errors.New("credential scope mismatch")
+	}
+
+	signedHeaders := strings.Split(fields["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(signedHeaders) {
+		return errors.New("signed headers are not sorted")
+	}
+	required := map[string]bool{"host": false, "x-amz-date": false, "x-amz-content-sha256": false}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		if _, ok := required[name]; ok {
+			required[name] = true
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	for name, signed := range required {
+		if !signed {
+			return errors.New(name + " is not signed")
+		}
+	}
+
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := []byte("AWS4" + secretKey)
+	for _, part := range []string{date.Format("20060102"), region, "s3", "aws4_request", stringToSign} {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	if !hmac.Equal([]byte(hex.EncodeToString(key)), []byte(fields["Signature"])) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func newTestS3Storage(t *testing.T, endpoint, secretKey string, pathStyle bool) *S3Storage {
+	t.Helper()
+	store, err := NewS3Storage(S3Config{
+		Endpoint:     endpoint,
+		Region:       testRegion,
+		Bucket:       testBucket,
+		AccessKey:    testAccessKey,
+		SecretKey:    secretKey,
+		UsePathStyle: pathStyle,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return testNow }
+	return store
+}
+
+func TestS3StoragePutGetDelete(t *testing.T) {
+	fake, server := newFakeS3(t)
+	store := newTestS3Storage(t, server.URL, testSecretKey, true)
+	ctx := context.Background()
+
+	key := "attachments/room 1/파일+name.txt"
+	content := []byte("hello attachment")
+	if err := store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := fake.types["/"+testBucket+"/"+key]; got != "text/plain" {
+		t.Errorf("stored content type = %q, want text/plain", got)
+	}
+
+	body, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Get = %q, want %q", got, content)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after delete: got %v, want ErrNotFound", err)
+	}
+	// 없는 객체 삭제는 성공으로 처리
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete missing object: %v", err)
+	}
+}
+
+func TestS3StorageVirtualHostedStyle(t *testing.T) {
+	fake, server := newFakeS3(t)
+	store := newTestS3Storage(t, "http://s3.test", testSecretKey, false)
+
+	// 버킷 이름이 붙은 호스트도 테스트 서버로 연결
+	addr := strings.TrimPrefix(server.URL, "http://")
+	store.client = &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+
+	content := []byte("virtual hosted")
+	if err := store.Put(context.Background(), "a/b.txt", bytes.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got := fake.hosts[len(fake.hosts)-1]; got != testBucket+".s3.test" {
+		t.Errorf("request host = %q, want %q", got, testBucket+".s3.test")
+	}
+	if _, ok := fake.objects["/a/b.txt"]; !ok {
+		t.Errorf("object not stored at /a/b.txt: %v", fake.objects)
+	}
+}
+
+func TestS3StorageRejectedSignature(t *testing.T) {
+	_, server := newFakeS3(t)
+	store := newTestS3Storage(t, server.URL, "wrong-secret", true)
+
+	err := store.Put(context.Background(), "a.txt", strings.NewReader("x"), 1, "text/plain")
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Put with wrong secret: got %v, want 403 error", err)
+	}
+}
+
+func TestS3StorageRejectsInvalidKey(t *testing.T) {
+	_, server := newFakeS3(t)
+	store := newTestS3Storage(t, server.URL, testSecretKey, true)
+
+	for _, key := range []string{"", "/abs", "a/../b", "a//b", `a\b`} {
+		if _, err := store.Get(context.Background(), key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Get(%q): got %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
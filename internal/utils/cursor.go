@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor 정렬 시각과 문서 ID로 불투명한 페이지 커서 생성
+func EncodeCursor(ts int64, id primitive.ObjectID) string {
+	raw := strconv.FormatInt(ts, 10) + ":" + id.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor EncodeCursor로 만든 커서 해석
+func DecodeCursor(cursor string) (int64, primitive.ObjectID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, primitive.NilObjectID, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return 0, primitive.NilObjectID, ErrInvalidCursor
+	}
+	ts, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, primitive.NilObjectID, ErrInvalidCursor
+	}
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return 0, primitive.NilObjectID, ErrInvalidCursor
+	}
+	return ts, id, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"chat-go-api/internal/models"
+	"sort"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -14,12 +15,231 @@ func ToMessageDTO(message *models.Message, getUserName func(userID primitive.Obj
 		return nil, err
 	}
 
+	messageType := message.Type
+	if messageType == "" {
+		messageType = models.MessageTypeText
+	}
+
 	return &models.MessageDTO{
 		ID:         message.ID,
 		RoomID:     message.RoomID,
 		SenderID:   message.SenderID,
 		SenderName: senderName,
+		Type:       messageType,
 		Content:    message.Content,
 		CreatedAt:  message.CreatedAt,
+		EditedAt:   message.EditedAt,
+		DeletedAt:  message.DeletedAt,
+
+		Attachments: toMessageAttachmentDTOs(message.Attachments),
+		Reactions:   toReactionDTOs(message.Reactions),
+		Mentions:    toMessageMentionDTOs(message.Mentions, getUserName),
+
+		ParentID:           message.ParentID,
+		ReplyCount:         message.ReplyCount,
+		LastReplyAt:        message.LastReplyAt,
+		ThreadParticipants: message.ThreadParticipants,
 	}, nil
 }
+
+func toMessageMentionDTOs(mentions []models.MessageMention, getUserName func(userID primitive.ObjectID) (string, error)) []models.MessageMentionDTO {
+	if len(mentions) == 0 {
+		return nil
+	}
+	dtos := make([]models.MessageMentionDTO, 0, len(mentions))
+	for _, mention := range mentions {
+		dto := models.MessageMentionDTO{
+			Type:   mention.Type,
+			UserID: mention.UserID,
+			Offset: mention.Offset,
+			Length: mention.Length,
+		}
+		if mention.UserID != nil {
+			dto.UserName, _ = getUserName(*mention.UserID)
+		}
+		dtos = append(dtos, dto)
+	}
+	return dtos
+}
+
+func toMessageAttachmentDTOs(attachments []models.MessageAttachment) []models.AttachmentDTO {
+	if len(attachments) == 0 {
+		return nil
+	}
+	dtos := make([]models.AttachmentDTO, 0, len(attachments))
+	for _, attachment := range attachments {
+		dtos = append(dtos, models.AttachmentDTO{
+			ID:          attachment.ID,
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			Status:      attachment.Status,
+			Width:       attachment.Width,
+			Height:      attachment.Height,
+			Placeholder: attachment.Placeholder,
+			Variants:    toAttachmentVariantDTOs(attachment.Variants),
+		})
+	}
+	return dtos
+}
+
+// ToAttachmentDTO 첨부파일을 DTO로 변환
+func ToAttachmentDTO(attachment *models.Attachment) *models.AttachmentDTO {
+	return &models.AttachmentDTO{
+		ID:          attachment.ID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		SHA256:      attachment.SHA256,
+		CreatedAt:   attachment.CreatedAt,
+		Status:      attachment.Status,
+		Width:       attachment.Width,
+		Height:      attachment.Height,
+		Placeholder: attachment.Placeholder,
+		Variants:    toAttachmentVariantDTOs(attachment.Variants),
+	}
+}
+
+func toAttachmentVariantDTOs(variants []models.AttachmentVariant) []models.AttachmentVariantDTO {
+	if len(variants) == 0 {
+		return nil
+	}
+	dtos := make([]models.AttachmentVariantDTO, 0, len(variants))
+	for _, variant := range variants {
+		dtos = append(dtos, models.AttachmentVariantDTO{
+			Name:        variant.Name,
+			Width:       variant.Width,
+			Height:      variant.Height,
+			Size:        variant.Size,
+			ContentType: variant.ContentType,
+		})
+	}
+	return dtos
+}
+
+// toReactionDTOs 리액션을 많이 받은 순으로 정렬된 집계로 변환
+func toReactionDTOs(reactions map[string][]primitive.ObjectID) []models.ReactionDTO {
+	dtos := []models.ReactionDTO{}
+	for emoji, userIDs := range reactions {
+		if len(userIDs) == 0 {
+			continue
+		}
+		dtos = append(dtos, models.ReactionDTO{Emoji: emoji, Count: len(userIDs), UserIDs: userIDs})
+	}
+	sort.Slice(dtos, func(i, j int) bool {
+		if dtos[i].Count != dtos[j].Count {
+			return dtos[i].Count > dtos[j].Count
+		}
+		return dtos[i].Emoji < dtos[j].Emoji
+	})
+	return dtos
+}
+
+// ToUserDTO 사용자를 DTO로 변환 (비밀번호 등 민감 정보 제외)
+func ToUserDTO(user *models.User) *models.UserDTO {
+	return &models.UserDTO{
+		ID:               user.ID,
+		Email:            user.Email,
+		Name:             user.Name,
+		Role:             user.Role,
+		IsEmailVerified:  user.IsEmailVerified,
+		IsDisabled:       user.IsDisabled,
+		DisabledAt:       user.DisabledAt,
+		TwoFactorEnabled: user.TOTPEnabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
+
+// ToSessionDTO 로그인 이력을 세션 DTO로 변환
+func ToSessionDTO(history *models.LoginHistory, currentSessionID string) *models.SessionDTO {
+	return &models.SessionDTO{
+		ID:         history.FamilyID,
+		UserAgent:  history.UserAgent,
+		IPAddress:  history.IPAddress,
+		CreatedAt:  history.CreatedAt,
+		LastUsedAt: history.LastUsedAt,
+		RevokedAt:  history.RevokedAt,
+		Current:    currentSessionID != "" && history.FamilyID == currentSessionID,
+	}
+}
+
+// ToChatRoomDTO 채팅방을 DTO로 변환
+// 1:1 대화방은 조회한 유저 기준 상대방 이름을 채팅방 이름으로 사용한다.
+func ToChatRoomDTO(room *models.ChatRoom, viewerID primitive.ObjectID, getUserName func(userID primitive.ObjectID) (string, error)) (*models.ChatRoomDTO, error) {
+	dto := &models.ChatRoomDTO{
+		ID:          room.ID,
+		Type:        room.Type,
+		Name:        room.Name,
+		Topic:       room.Topic,
+		Description: room.Description,
+		AvatarURL:   room.AvatarURL,
+		Visibility:  room.Visibility,
+		Admins:      room.Admins,
+		Members:     room.Members,
+		CreatedAt:   room.CreatedAt,
+		UpdatedAt:   room.UpdatedAt,
+		ArchivedAt:  room.ArchivedAt,
+
+		LastMessage:    room.LastMessage,
+		LastActivityAt: room.LastActivityAt,
+	}
+	if dto.LastActivityAt == 0 {
+		dto.LastActivityAt = room.CreatedAt
+	}
+	if dto.Type == "" {
+		dto.Type = models.RoomTypeGroup
+	}
+	if dto.Visibility == "" {
+		dto.Visibility = models.RoomVisibilityPrivate
+	}
+	if dto.Admins == nil {
+		dto.Admins = []primitive.ObjectID{}
+	}
+
+	if !room.IsDirect() && !room.OwnerID.IsZero() {
+		ownerID := room.OwnerID
+		dto.OwnerID = &ownerID
+	}
+
+	if room.IsDirect() {
+		for _, memberID := range room.Members {
+			if memberID == viewerID {
+				continue
+			}
+			peerID := memberID
+			peerName, err := getUserName(peerID)
+			if err != nil {
+				return nil, err
+			}
+			dto.PeerID = &peerID
+			dto.Name = peerName
+			break
+		}
+	}
+
+	return dto, nil
+}
+
+// ToPublicRoomDTO 공개 디렉터리용 채팅방 DTO 변환
+func ToPublicRoomDTO(room *models.ChatRoom, viewerID primitive.ObjectID) *models.PublicRoomDTO {
+	isMember := false
+	for _, memberID := range room.Members {
+		if memberID == viewerID {
+			isMember = true
+			break
+		}
+	}
+
+	return &models.PublicRoomDTO{
+		ID:          room.ID,
+		Name:        room.Name,
+		Topic:       room.Topic,
+		Description: room.Description,
+		AvatarURL:   room.AvatarURL,
+		Visibility:  room.Visibility,
+		MemberCount: len(room.Members),
+		IsMember:    isMember,
+		CreatedAt:   room.CreatedAt,
+	}
+}
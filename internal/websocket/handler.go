@@ -1,12 +1,14 @@
 package websocket
 
 import (
+	"chat-go-api/internal/models"
 	"chat-go-api/internal/services"
-	"fmt"
+	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/gorilla/websocket"
 )
 
@@ -16,7 +18,7 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
-func WebSocketHandler(manager *Manager, wsService *services.WebSocketService) http.HandlerFunc {
+func WebSocketHandler(manager *Manager, wsService *services.WebSocketService, authService *services.AuthService) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// WebSocket 연결 업그레이드
 		conn, err := upgrader.Upgrade(w, r, nil)
@@ -33,33 +35,19 @@ func WebSocketHandler(manager *Manager, wsService *services.WebSocketService) ht
 			return
 		}
 
-		// 토큰 검증
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte("access-secret-key"), nil
-		})
-		if err != nil || !token.Valid {
-			conn.WriteMessage(websocket.TextMessage, []byte("Invalid token"))
-			conn.Close()
-			return
-		}
-
-		// 사용자 ID 추출
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			conn.WriteMessage(websocket.TextMessage, []byte("Invalid token claims"))
+		// 토큰 및 세션 검증
+		claims, err := authService.ValidateAccessToken(tokenString)
+		if errors.Is(err, services.ErrAccountDisabled) {
+			conn.WriteMessage(websocket.TextMessage, []byte("Account disabled"))
 			conn.Close()
 			return
 		}
-
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			conn.WriteMessage(websocket.TextMessage, []byte("Invalid user_id in token"))
+		if err != nil {
+			conn.WriteMessage(websocket.TextMessage, []byte("Invalid token"))
 			conn.Close()
 			return
 		}
+		userID := claims.UserID
 
 		// 채팅방 ID 가져오기
 		roomID := r.URL.Query().Get("room_id")
@@ -69,8 +57,14 @@ func WebSocketHandler(manager *Manager, wsService *services.WebSocketService) ht
 			return
 		}
 
+		// 채팅방 접근 권한 확인
+		if err := wsService.AuthorizeJoin(roomID, userID); err != nil {
+			closeWithRoomError(conn, err)
+			return
+		}
+
 		// 클라이언트 등록
-		manager.RegisterClientWithUser(roomID, conn, userID)
+		manager.RegisterClientWithUser(roomID, conn, userID, claims.FamilyID)
 
 		// 메시지 수신 루프
 		go func() {
@@ -90,9 +84,62 @@ func WebSocketHandler(manager *Manager, wsService *services.WebSocketService) ht
 
 				// 메시지 처리
 				if err := wsService.HandleIncomingMessage(roomID, senderID, message); err != nil {
+					if errors.Is(err, services.ErrRoomAccessDenied) || errors.Is(err, services.ErrRoomNotFound) {
+						closeWithRoomError(conn, err)
+						break
+					}
 					log.Printf("Failed to handle WebSocket message: %v", err)
+					manager.SendEventToConn(roomID, conn, errorEvent(roomID, err))
 				}
 			}
 		}()
 	}
 }
+
+// 채팅방 권한 에러에 맞는 종료 코드로 연결 종료
+func closeWithRoomError(conn *websocket.Conn, err error) {
+	switch {
+	case errors.Is(err, services.ErrRoomNotFound):
+		closeWithCode(conn, CloseRoomNotFound, err.Error())
+	case errors.Is(err, services.ErrRoomAccessDenied):
+		closeWithCode(conn, CloseForbidden, err.Error())
+	default:
+		log.Printf("Failed to authorize room access: %v", err)
+		closeWithCode(conn, websocket.CloseInternalServerErr, "internal server error")
+	}
+}
+
+// 클라이언트에 그대로 알려줘도 되는 요청 거부 사유
+var rejectedActionErrors = []error{
+	services.ErrRoomArchived, services.ErrUnknownAction, services.ErrRoomPermissionDenied,
+	services.ErrMessageNotFound, services.ErrMessageDeleted, services.ErrInvalidMessageContent,
+	services.ErrInvalidThreadParent, services.ErrMessageEditForbidden, services.ErrMessageEditWindowExpired,
+	services.ErrMessageDeleteForbidden, services.ErrInvalidReaction, services.ErrReactionExists,
+	services.ErrReactionNotFound, services.ErrInvalidAttachment, services.ErrAttachmentNotFound,
+	services.ErrAttachmentProcessing, services.ErrAttachmentUnavailable,
+}
+
+// 처리하지 못한 요청을 보낸 연결에 알릴 에러 이벤트 (내부 오류 내용은 숨김)
+func errorEvent(roomID string, err error) *models.RoomEvent {
+	message := "internal server error"
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		message = "invalid message format"
+	default:
+		for _, rejected := range rejectedActionErrors {
+			if errors.Is(err, rejected) {
+				message = err.Error()
+				break
+			}
+		}
+	}
+
+	return &models.RoomEvent{
+		Type:      models.EventError,
+		RoomID:    roomID,
+		Data:      map[string]string{"error": message},
+		CreatedAt: time.Now().Unix(),
+	}
+}
@@ -5,18 +5,37 @@ import (
 	"chat-go-api/internal/models"
 	"encoding/json"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
+// 애플리케이션 정의 WebSocket 종료 코드
+const (
+	CloseSessionRevoked = 4001
+	CloseForbidden      = 4003
+	CloseRoomNotFound   = 4004
+	CloseRoomClosed     = 4005
+)
+
 type ManagerInterface interface {
 	BroadcastToRoom(roomID string, message models.MessageDTO) error
 	RegisterClient(roomID string, conn *websocket.Conn)
 	UnregisterClient(roomID string, conn *websocket.Conn)
 }
 
+// client 연결된 클라이언트 정보
+type client struct {
+	roomID    string
+	userID    string
+	sessionID string
+}
+
 type Manager struct {
-	rooms      map[string]map[*websocket.Conn]string // roomID -> conn -> userID
+	mu         sync.Mutex
+	rooms      map[string]map[*websocket.Conn]*client // roomID -> conn -> client
+	users      map[string]map[*websocket.Conn]*client // userID -> conn -> client (유저 단위 전송용)
 	broadcast  chan common.BroadcastMessage
 	register   chan common.RegisterMessage
 	unregister chan common.UnregisterMessage
@@ -24,24 +43,66 @@ type Manager struct {
 
 func NewManager() *Manager {
 	return &Manager{
-		rooms:      make(map[string]map[*websocket.Conn]string),
+		rooms:      make(map[string]map[*websocket.Conn]*client),
+		users:      make(map[string]map[*websocket.Conn]*client),
 		broadcast:  make(chan common.BroadcastMessage),
 		register:   make(chan common.RegisterMessage),
 		unregister: make(chan common.UnregisterMessage),
 	}
 }
 
-func (m *Manager) RegisterClientWithUser(roomID string, conn *websocket.Conn, userID string) {
-	if _, ok := m.rooms[roomID]; !ok {
-		m.rooms[roomID] = make(map[*websocket.Conn]string)
+func (m *Manager) RegisterClientWithUser(roomID string, conn *websocket.Conn, userID, sessionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addClient(&client{roomID: roomID, userID: userID, sessionID: sessionID}, conn)
+}
+
+// 채팅방과 유저 색인에 연결 추가 (m.mu를 잡은 상태에서 호출)
+func (m *Manager) addClient(c *client, conn *websocket.Conn) {
+	if _, ok := m.rooms[c.roomID]; !ok {
+		m.rooms[c.roomID] = make(map[*websocket.Conn]*client)
+	}
+	m.rooms[c.roomID][conn] = c
+
+	if _, ok := m.users[c.userID]; !ok {
+		m.users[c.userID] = make(map[*websocket.Conn]*client)
+	}
+	m.users[c.userID][conn] = c
+}
+
+// 채팅방과 유저 색인에서 연결 제거 (m.mu를 잡은 상태에서 호출)
+// 등록되지 않은 연결이면 false를 반환한다.
+func (m *Manager) removeClient(roomID string, conn *websocket.Conn) bool {
+	clients, ok := m.rooms[roomID]
+	if !ok {
+		return false
+	}
+	c, exists := clients[conn]
+	if !exists {
+		return false
+	}
+	delete(clients, conn)
+	if len(clients) == 0 {
+		delete(m.rooms, roomID)
 	}
-	m.rooms[roomID][conn] = userID
+
+	if conns, ok := m.users[c.userID]; ok {
+		delete(conns, conn)
+		if len(conns) == 0 {
+			delete(m.users, c.userID)
+		}
+	}
+	return true
 }
 
 func (m *Manager) GetUserID(roomID string, conn *websocket.Conn) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if room, ok := m.rooms[roomID]; ok {
-		if userID, exists := room[conn]; exists {
-			return userID, true
+		if c, exists := room[conn]; exists {
+			return c.userID, true
 		}
 	}
 	return "", false
@@ -49,26 +110,150 @@ func (m *Manager) GetUserID(roomID string, conn *websocket.Conn) (string, bool)
 
 // Run 및 기타 기존 메서드는 동일
 func (m *Manager) BroadcastToRoom(roomID string, message *models.MessageDTO) error {
-	if clients, ok := m.rooms[roomID]; ok {
-		for conn := range clients {
-			// 메시지 직렬화
-			data, err := json.Marshal(message)
-			if err != nil {
-				log.Printf("Failed to serialize message: %v", err)
-				continue
-			}
+	return m.broadcastJSON(roomID, message)
+}
 
-			// 메시지 전송
-			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-				log.Printf("Failed to send message: %v", err)
-				conn.Close()
-				delete(clients, conn)
-			}
+// BroadcastEventToRoom 채팅방 이벤트 브로드캐스트
+func (m *Manager) BroadcastEventToRoom(roomID string, event *models.RoomEvent) error {
+	return m.broadcastJSON(roomID, event)
+}
+
+// 채팅방의 모든 연결에 JSON 페이로드 전송
+func (m *Manager) broadcastJSON(roomID string, payload interface{}) error {
+	// 메시지 직렬화
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Failed to serialize message: %v", err)
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for conn := range m.rooms[roomID] {
+		// 메시지 전송
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			log.Printf("Failed to send message: %v", err)
+			conn.Close()
+			m.removeClient(roomID, conn)
 		}
 	}
 	return nil
 }
 
+// SendEventToUser 유저의 모든 연결에 이벤트 전송 (어느 채팅방에 접속해 있든 전달)
+// 접속 중인 연결이 하나도 없으면 false를 반환한다.
+func (m *Manager) SendEventToUser(userID string, event *models.RoomEvent) bool {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to serialize event: %v", err)
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delivered := false
+	for conn, c := range m.users[userID] {
+		if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			log.Printf("Failed to send event: %v", err)
+			conn.Close()
+			m.removeClient(c.roomID, conn)
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+// SendEventToConn 특정 연결에만 이벤트 전송 (요청 거부 알림 등)
+func (m *Manager) SendEventToConn(roomID string, conn *websocket.Conn, event *models.RoomEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Failed to serialize event: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.rooms[roomID][conn]; !exists {
+		return
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+		log.Printf("Failed to send event: %v", err)
+		conn.Close()
+		m.removeClient(roomID, conn)
+	}
+}
+
+// IsUserConnected 유저가 어느 채팅방에든 접속해 있는지 확인
+func (m *Manager) IsUserConnected(userID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.users[userID]) > 0
+}
+
+// DisconnectUserFromRoom 특정 채팅방에서 유저의 연결 종료 (멤버 제외 시)
+func (m *Manager) DisconnectUserFromRoom(roomID, userID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for conn, c := range m.users[userID] {
+		if c.roomID == roomID {
+			closeWithCode(conn, CloseForbidden, "removed from room")
+			m.removeClient(roomID, conn)
+		}
+	}
+}
+
+// DisconnectSession 특정 세션의 모든 연결 종료
+func (m *Manager) DisconnectSession(sessionID string) {
+	m.disconnectWhere(func(c *client) bool { return c.sessionID == sessionID }, CloseSessionRevoked, "session revoked")
+}
+
+// DisconnectUser 특정 유저의 모든 연결 종료
+func (m *Manager) DisconnectUser(userID string) {
+	m.disconnectWhere(func(c *client) bool { return c.userID == userID }, CloseSessionRevoked, "session revoked")
+}
+
+// 조건에 맞는 연결을 종료 코드와 함께 닫음
+func (m *Manager) disconnectWhere(match func(c *client) bool, code int, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, clients := range m.rooms {
+		for conn, c := range clients {
+			if !match(c) {
+				continue
+			}
+			closeWithCode(conn, code, reason)
+			m.removeClient(c.roomID, conn)
+		}
+	}
+}
+
+// CloseRoom 채팅방의 모든 연결 종료 (채팅방 삭제 시)
+func (m *Manager) CloseRoom(roomID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for conn := range m.rooms[roomID] {
+		closeWithCode(conn, CloseRoomClosed, "room closed")
+		m.removeClient(roomID, conn)
+	}
+}
+
+// closeWithCode 종료 프레임 전송 후 연결 닫기
+func closeWithCode(conn *websocket.Conn, code int, reason string) {
+	deadline := time.Now().Add(time.Second)
+	if err := conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline); err != nil {
+		log.Printf("Failed to send close frame: %v", err)
+	}
+	conn.Close()
+}
+
 func (m *Manager) RegisterClient(roomID string, conn *websocket.Conn) {
 	m.register <- common.RegisterMessage{
 		RoomID: roomID,
@@ -88,38 +273,33 @@ func (m *Manager) Run() {
 		select {
 		case msg := <-m.register:
 			// 클라이언트 등록
-			if _, ok := m.rooms[msg.RoomID]; !ok {
-				m.rooms[msg.RoomID] = make(map[*websocket.Conn]string)
-			}
-			m.rooms[msg.RoomID][msg.Conn] = msg.UserID // UserID 저장
+			m.mu.Lock()
+			m.addClient(&client{roomID: msg.RoomID, userID: msg.UserID, sessionID: msg.SessionID}, msg.Conn) // UserID 저장
+			m.mu.Unlock()
 
 		case msg := <-m.unregister:
 			// 클라이언트 해제
-			if clients, ok := m.rooms[msg.RoomID]; ok {
-				if _, exists := clients[msg.Conn]; exists {
-					delete(clients, msg.Conn)
-					msg.Conn.Close()
-				}
-				if len(clients) == 0 {
-					delete(m.rooms, msg.RoomID)
-				}
+			m.mu.Lock()
+			if m.removeClient(msg.RoomID, msg.Conn) {
+				msg.Conn.Close()
 			}
+			m.mu.Unlock()
 
 		case msg := <-m.broadcast:
 			// 메시지 브로드캐스트
-			if clients, ok := m.rooms[msg.RoomID]; ok {
-				for conn := range clients {
-					data, err := json.Marshal(msg.Message)
-					if err != nil {
-						log.Printf("Failed to serialize message: %v", err)
-						continue
-					}
-					if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
-						conn.Close()
-						delete(clients, conn)
-					}
+			m.mu.Lock()
+			for conn := range m.rooms[msg.RoomID] {
+				data, err := json.Marshal(msg.Message)
+				if err != nil {
+					log.Printf("Failed to serialize message: %v", err)
+					continue
+				}
+				if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+					conn.Close()
+					m.removeClient(msg.RoomID, conn)
 				}
 			}
+			m.mu.Unlock()
 		}
 	}
 }
@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestManagerUserIndex(t *testing.T) {
+	m := NewManager()
+	roomA, roomB := new(websocket.Conn), new(websocket.Conn)
+	other := new(websocket.Conn)
+
+	m.RegisterClientWithUser("room-a", roomA, "alice", "session-1")
+	m.RegisterClientWithUser("room-b", roomB, "alice", "session-1")
+	m.RegisterClientWithUser("room-a", other, "bob", "session-2")
+
+	if !m.IsUserConnected("alice") || !m.IsUserConnected("bob") {
+		t.Fatal("IsUserConnected() = false for registered users")
+	}
+	if got := len(m.users["alice"]); got != 2 {
+		t.Fatalf("alice has %d indexed connections, want 2", got)
+	}
+
+	m.mu.Lock()
+	m.removeClient("room-a", roomA)
+	m.mu.Unlock()
+	if !m.IsUserConnected("alice") {
+		t.Error("IsUserConnected() = false while alice is still in room-b")
+	}
+
+	m.mu.Lock()
+	m.removeClient("room-b", roomB)
+	removed := m.removeClient("room-b", roomB)
+	m.mu.Unlock()
+	if removed {
+		t.Error("removeClient() = true for an already removed connection")
+	}
+	if m.IsUserConnected("alice") {
+		t.Error("IsUserConnected() = true after all of alice's connections were removed")
+	}
+	if _, ok := m.users["alice"]; ok {
+		t.Error("empty user entry was not cleaned up")
+	}
+	if _, ok := m.rooms["room-b"]; ok {
+		t.Error("empty room entry was not cleaned up")
+	}
+	if !m.IsUserConnected("bob") {
+		t.Error("IsUserConnected() = false for bob after removing alice")
+	}
+}
@@ -2,23 +2,101 @@ package utils
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
 type ServerConfig struct {
-	Port string `yaml:"port"`
+	Port        string `yaml:"port"`
+	FrontendURL string `yaml:"frontend_url"` // 비밀번호 재설정 링크에 사용할 프론트엔드 주소
+	// X-Forwarded-For 헤더를 신뢰할 리버스 프록시 주소 (IP 또는 CIDR), 비어 있으면 헤더를 무시
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// TrustedProxyPrefixes 신뢰할 프록시 주소를 CIDR 목록으로 변환 (단일 IP는 /32, /128로 취급)
+func (c ServerConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := []netip.Prefix{}
+	for _, proxy := range c.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if strings.Contains(proxy, "/") {
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
 }
 
 type DatabaseConfig struct {
 	Url string `yaml:"url"`
 }
 
+type ChatConfig struct {
+	MessageEditWindow int `yaml:"message_edit_window"` // 메시지 수정 가능 시간(초), 0이면 제한 없음
+	// 삭제된 메시지의 원래 내용 보관 시간(초), 0이면 삭제 즉시 지움
+	DeletedMessageRetention int `yaml:"deleted_message_retention"`
+}
+
+type SearchConfig struct {
+	Backend string `yaml:"backend"` // mongo(기본값) 또는 memory
+}
+
+type StorageConfig struct {
+	Backend       string `yaml:"backend"`         // local(기본값) 또는 s3
+	LocalDir      string `yaml:"local_dir"`       // local 저장소 경로
+	S3Endpoint    string `yaml:"s3_endpoint"`     // S3 호환 저장소 주소 (예: https://s3.ap-northeast-2.amazonaws.com)
+	S3Region      string `yaml:"s3_region"`       // 서명에 사용할 리전
+	S3Bucket      string `yaml:"s3_bucket"`       // 버킷 이름
+	S3AccessKey   string `yaml:"s3_access_key"`   // 액세스 키
+	S3SecretKey   string `yaml:"s3_secret_key"`   // 시크릿 키
+	S3PathStyle   bool   `yaml:"s3_path_style"`   // 경로 방식 주소 사용 여부 (MinIO 등)
+	MaxUploadSize int64  `yaml:"max_upload_size"` // 첨부파일 최대 크기(바이트)
+	URLTTL        int    `yaml:"url_ttl"`         // 다운로드 URL 유효 시간(초)
+	SigningKey    string `yaml:"signing_key"`     // 다운로드 URL 서명 키
+	UnclaimedTTL  int    `yaml:"unclaimed_ttl"`   // 메시지에 연결되지 않은 업로드 보관 시간(초)
+}
+
+// 예전 설정 파일에 들어 있던 기본 서명 키 (공개되어 있으므로 사용 금지)
+const placeholderSigningKey = "default-attachment-signing-key"
+
+const minSigningKeyLength = 32
+
+// 다운로드 URL 서명 키 검증
+// 키가 비어 있거나 공개된 기본값이면 누구나 다운로드 URL을 위조할 수 있으므로 서버를 시작하지 않는다.
+func (c StorageConfig) ValidateSigningKey() error {
+	switch {
+	case c.SigningKey == "":
+		return fmt.Errorf("storage.signing_key is not set")
+	case c.SigningKey == placeholderSigningKey:
+		return fmt.Errorf("storage.signing_key must not be the default placeholder")
+	case len(c.SigningKey) < minSigningKeyLength:
+		return fmt.Errorf("storage.signing_key must be at least %d characters", minSigningKeyLength)
+	}
+	return nil
+}
+
 type Config struct {
 	Server   ServerConfig   `yaml:"server"`
 	Database DatabaseConfig `yaml:"database"`
+	Chat     ChatConfig     `yaml:"chat"`
+	Search   SearchConfig   `yaml:"search"`
+	Storage  StorageConfig  `yaml:"storage"`
 }
 
 func LoadConfig(filename string) (*Config, error) {
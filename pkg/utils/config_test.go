@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStorageConfigValidateSigningKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"empty", "", true},
+		{"placeholder", "default-attachment-signing-key", true},
+		{"too short", "short-key", true},
+		{"valid", strings.Repeat("k", minSigningKeyLength), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := StorageConfig{SigningKey: tt.key}.ValidateSigningKey()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateSigningKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServerConfigTrustedProxyPrefixes(t *testing.T) {
+	prefixes, err := ServerConfig{TrustedProxies: []string{"10.0.0.0/8", " 192.168.1.10 ", "", "::ffff:172.16.0.1", "2001:db8::/32"}}.TrustedProxyPrefixes()
+	if err != nil {
+		t.Fatalf("TrustedProxyPrefixes() error = %v", err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.10/32", "172.16.0.1/32", "2001:db8::/32"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("TrustedProxyPrefixes() = %v, want %v", prefixes, want)
+	}
+	for i, prefix := range prefixes {
+		if prefix.String() != want[i] {
+			t.Errorf("prefix[%d] = %s, want %s", i, prefix, want[i])
+		}
+	}
+
+	for _, invalid := range []string{"not-an-ip", "10.0.0.0/33"} {
+		if _, err := (ServerConfig{TrustedProxies: []string{invalid}}).TrustedProxyPrefixes(); err == nil {
+			t.Errorf("TrustedProxyPrefixes(%q) error = nil, want error", invalid)
+		}
+	}
+}